@@ -2,15 +2,23 @@ package main
 
 import (
 	"context"
+	"image"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
+	// Branch opening hours need the timezone database, which the runtime image doesn't ship
+	_ "time/tzdata"
 
 	config "juansecalvinio/tepidolacuenta/config"
 	database "juansecalvinio/tepidolacuenta/internal/database"
 	middleware "juansecalvinio/tepidolacuenta/internal/middleware"
 	pkg "juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/pkg/qrcode"
 
 	authHandler "juansecalvinio/tepidolacuenta/internal/auth/handler"
 	authRepo "juansecalvinio/tepidolacuenta/internal/auth/repository"
@@ -32,6 +40,14 @@ import (
 	tableRepo "juansecalvinio/tepidolacuenta/internal/table/repository"
 	tableUseCase "juansecalvinio/tepidolacuenta/internal/table/usecase"
 
+	zoneHandler "juansecalvinio/tepidolacuenta/internal/zone/handler"
+	zoneRepo "juansecalvinio/tepidolacuenta/internal/zone/repository"
+	zoneUseCase "juansecalvinio/tepidolacuenta/internal/zone/usecase"
+
+	sessionHandler "juansecalvinio/tepidolacuenta/internal/session/handler"
+	sessionRepo "juansecalvinio/tepidolacuenta/internal/session/repository"
+	sessionUseCase "juansecalvinio/tepidolacuenta/internal/session/usecase"
+
 	requestDomain "juansecalvinio/tepidolacuenta/internal/request/domain"
 	requestHandler "juansecalvinio/tepidolacuenta/internal/request/handler"
 	requestRepo "juansecalvinio/tepidolacuenta/internal/request/repository"
@@ -112,7 +128,21 @@ func main() {
 
 	// Initialize services
 	jwtService := pkg.NewJWTService(cfg.JWTSecret)
-	qrService := pkg.NewQRService(cfg.FrontendBaseURL)
+	qrService := pkg.NewQRService(cfg.FrontendBaseURL, cfg.ShortLinkBaseURL, cfg.QRSigningSecret, cfg.LegacyQRAcceptedUntil)
+	noteSanitizer := pkg.NewNoteSanitizer(requestDomain.MaxNoteLength, cfg.RequestNoteBannedWords)
+	ipLimiter := pkg.NewSlidingWindowLimiter(cfg.RateLimitIPMax, cfg.RateLimitWindow)
+	tableRequestLimiter := pkg.NewSlidingWindowLimiter(cfg.RateLimitTableRequestMax, cfg.RateLimitWindow)
+	tableVenueInfoLimiter := pkg.NewSlidingWindowLimiter(cfg.RateLimitTableVenueInfoMax, cfg.RateLimitWindow)
+	// Optional logo drawn in the center of rendered table QR images
+	var qrLogo image.Image
+	if cfg.QRLogoPath != "" {
+		qrLogo, err = qrcode.LoadLogo(cfg.QRLogoPath)
+		if err != nil {
+			log.Printf("Warning: QR logo not loaded: %v", err)
+		} else {
+			log.Println("✓ QR logo loaded")
+		}
+	}
 	emailService := pkg.NewEmailService(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
 
 	// Initialize WebSocket hub
@@ -140,6 +170,7 @@ func main() {
 
 	// Branch repository (needed early by the invitation usecase for branch-scoped codes)
 	branchRepository := branchRepo.NewMongoRepository(db.Database)
+	branchSettingsRepository := branchRepo.NewMongoSettingsRepository(db.Database)
 
 	// Initialize Invitation module
 	invitationRepository := invitationRepo.NewMongoRepository(db.Database)
@@ -152,21 +183,33 @@ func main() {
 
 	invitationHdlr := invitationHandler.NewInvitationHandler(invitationService, authService)
 
+	tableRepository := tableRepo.NewMongoRepository(db.Database)
+	shortLinkRepository := tableRepo.NewMongoShortLinkRepository(db.Database)
+	zoneRepository := zoneRepo.NewMongoRepository(db.Database)
+
 	// Initialize Branch module (branchRepository created earlier)
-	branchService := branchUseCase.NewBranchUseCase(branchRepository, restaurantRepository, subscriptionRepository, planRepository)
+	branchService := branchUseCase.NewBranchUseCase(branchRepository, branchSettingsRepository, restaurantRepository, subscriptionRepository, planRepository, zoneRepository, tableRepository, shortLinkRepository, db, qrService)
 	branchHdlr := branchHandler.NewBranchHandler(branchService)
 
 	// Initialize Table module
-	tableRepository := tableRepo.NewMongoRepository(db.Database)
-	tableService := tableUseCase.NewTableUseCase(tableRepository, branchRepository, restaurantRepository, subscriptionRepository, planRepository, qrService)
+	tableService := tableUseCase.NewTableUseCase(tableRepository, shortLinkRepository, branchRepository, restaurantRepository, zoneRepository, subscriptionRepository, planRepository, db, qrService, qrLogo)
 	tableHdlr := tableHandler.NewTableHandler(tableService)
 
+	// Initialize Zone module
+	zoneService := zoneUseCase.NewZoneUseCase(zoneRepository, branchRepository, restaurantRepository, tableRepository)
+	zoneHdlr := zoneHandler.NewZoneHandler(zoneService)
+
+	// Initialize Session module
+	sessionRepository := sessionRepo.NewMongoRepository(db.Database)
+	sessionService := sessionUseCase.NewSessionUseCase(sessionRepository, branchRepository, restaurantRepository, tableRepository)
+	sessionHdlr := sessionHandler.NewSessionHandler(sessionService)
+
 	// Initialize Setup module
-	setupService := setupUseCase.NewSetupUseCase(restaurantRepository, branchRepository, tableRepository, qrService)
+	setupService := setupUseCase.NewSetupUseCase(restaurantRepository, branchRepository, tableRepository, shortLinkRepository, db, qrService)
 	setupHdlr := setupHandler.NewSetupHandler(setupService)
 
 	// Initialize Subscription module
-	subscriptionService := subscriptionUseCase.NewSubscriptionUseCase(planRepository, subscriptionRepository, restaurantRepository)
+	subscriptionService := subscriptionUseCase.NewSubscriptionUseCase(planRepository, subscriptionRepository, restaurantRepository, branchRepository, tableRepository, db)
 	subscriptionHdlr := subscriptionHandler.NewSubscriptionHandler(subscriptionService)
 
 	// Initialize Payment module
@@ -178,12 +221,12 @@ func main() {
 	notifyPaymentFunc := func(restaurantID primitive.ObjectID, event *paymentDomain.PaymentEvent) {
 		hub.Broadcast(restaurantID, event)
 	}
-	paymentService := paymentUseCase.NewPaymentUseCase(paymentRepository, planRepository, subscriptionRepository, restaurantRepository, mpClient, cfg.MercadoPagoNotificationURL, cfg.FrontendBaseURL, notifyPaymentFunc)
+	paymentService := paymentUseCase.NewPaymentUseCase(paymentRepository, planRepository, subscriptionService, restaurantRepository, mpClient, cfg.MercadoPagoNotificationURL, cfg.FrontendBaseURL, notifyPaymentFunc)
 	paymentHdlr := paymentHandler.NewPaymentHandler(paymentService)
 	log.Println("✓ MercadoPago client initialized")
 
 	// Run pending migrations
-	migrationRunner := migration.NewRunner(db.Database, migration.All())
+	migrationRunner := migration.NewRunner(db.Database, migration.All(qrService))
 	if err := migrationRunner.Run(context.Background()); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
@@ -195,21 +238,58 @@ func main() {
 
 	// Initialize Request module
 	requestRepository := requestRepo.NewMongoRepository(db.Database)
+	requestArchiveRepository := requestRepo.NewMongoArchiveRepository(db.Database)
+	scanRepository := requestRepo.NewMongoScanRepository(db.Database)
 
 	// Notification function for WebSocket
-	notifyFunc := func(restaurantID primitive.ObjectID, request *requestDomain.Request) {
-		hub.Broadcast(restaurantID, request)
+	notifyFunc := func(restaurantID primitive.ObjectID, zoneIDs []primitive.ObjectID, data interface{}) {
+		hub.BroadcastToZones(restaurantID, zoneIDs, data)
 	}
 
 	requestService := requestUseCase.NewRequestUseCase(
 		requestRepository,
+		requestArchiveRepository,
+		scanRepository,
 		restaurantRepository,
 		branchRepository,
+		branchSettingsRepository,
 		tableRepository,
+		shortLinkRepository,
+		sessionRepository,
+		db,
 		qrService,
+		noteSanitizer,
+		tableRequestLimiter,
+		tableVenueInfoLimiter,
 		notifyFunc,
 	)
-	requestHdlr := requestHandler.NewRequestHandler(requestService, hub, jwtService)
+	requestHdlr := requestHandler.NewRequestHandler(requestService, hub, jwtService, ipLimiter)
+
+	// Background jobs stop on SIGINT/SIGTERM; the server waits for them before exiting
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var jobs sync.WaitGroup
+
+	// Drop rate limit entries of clients that went quiet
+	jobs.Go(func() {
+		pkg.RunEvery(shutdownCtx, "rate limit pruning", cfg.RateLimitWindow, func(ctx context.Context) error {
+			ipLimiter.Prune()
+			tableRequestLimiter.Prune()
+			tableVenueInfoLimiter.Prune()
+			return nil
+		})
+	})
+
+	// Archive requests past each restaurant's retention period
+	jobs.Go(func() {
+		pkg.RunEvery(shutdownCtx, "request archival", cfg.RequestArchiveInterval, func(ctx context.Context) error {
+			archived, err := requestService.ArchiveExpired(ctx)
+			if archived > 0 {
+				log.Printf("✓ Archived %d expired requests", archived)
+			}
+			return err
+		})
+	})
 
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
@@ -217,6 +297,11 @@ func main() {
 	// Initialize Gin router
 	router := gin.Default()
 
+	// Only trust X-Forwarded-For from our own proxies, so clients can't pick the IP the rate limits see
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Sentry middleware (must be before other middleware)
 	if cfg.SentryDSN != "" {
 		router.Use(sentrygin.New(sentrygin.Options{
@@ -283,6 +368,12 @@ func main() {
 			// Table routes
 			tableHdlr.RegisterRoutes(protected)
 
+			// Zone routes
+			zoneHdlr.RegisterRoutes(protected)
+
+			// Session routes
+			sessionHdlr.RegisterRoutes(protected)
+
 			// Setup routes
 			setupHdlr.RegisterRoutes(protected)
 
@@ -306,10 +397,33 @@ func main() {
 		requestHdlr.RegisterWebSocketRoute(v1)
 	}
 
-	log.Printf("🚀 Server running on port %s\n", cfg.Port)
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	// QR short links (public, outside /api/v1 to keep printed URLs short)
+	requestHdlr.RegisterShortLinkRoute(router)
+
+	server := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: router,
 	}
+
+	go func() {
+		log.Printf("🚀 Server running on port %s\n", cfg.Port)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-shutdownCtx.Done()
+	log.Println("Shutting down...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Server shutdown failed: %v", err)
+	}
+
+	// Let a running archival batch finish before the database connection closes
+	jobs.Wait()
+	log.Println("✓ Server stopped")
 }
 
 func seedPlans(ctx context.Context, repo subscriptionRepo.PlanRepository) error {
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -28,6 +31,17 @@ type Config struct {
 	MercadoPagoAccessToken      string
 	MercadoPagoWebhookSecret    string
 	MercadoPagoNotificationURL  string
+	RequestNoteBannedWords      []string
+	RequestArchiveInterval      time.Duration
+	RateLimitWindow             time.Duration
+	RateLimitIPMax              int
+	RateLimitTableRequestMax    int
+	RateLimitTableVenueInfoMax  int
+	TrustedProxies              []string
+	QRLogoPath                  string
+	QRSigningSecret             string
+	LegacyQRAcceptedUntil       time.Time
+	ShortLinkBaseURL            string
 }
 
 func Load() (*Config, error) {
@@ -41,9 +55,16 @@ func Load() (*Config, error) {
 		originsSlice[i] = strings.TrimSpace(originsSlice[i])
 	}
 
+	jwtSecret := getEnv("JWT_SECRET", "")
+
+	legacyQRAcceptedUntil, err := getEnvDate("LEGACY_QR_ACCEPTED_UNTIL")
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		MongoURI:           getEnv("MONGODB_URI", ""),
-		JWTSecret:          getEnv("JWT_SECRET", ""),
+		JWTSecret:          jwtSecret,
 		Port:               getEnv("PORT", "8080"),
 		GinMode:            getEnv("GIN_MODE", "debug"),
 		CORSAllowedOrigins: originsSlice,
@@ -62,6 +83,17 @@ func Load() (*Config, error) {
 		MercadoPagoAccessToken:     getEnv("MERCADOPAGO_ACCESS_TOKEN", ""),
 		MercadoPagoWebhookSecret:   getEnv("MERCADOPAGO_WEBHOOK_SECRET", ""),
 		MercadoPagoNotificationURL: getEnv("MERCADOPAGO_NOTIFICATION_URL", ""),
+		RequestNoteBannedWords:     getEnvList("REQUEST_NOTE_BANNED_WORDS"),
+		RequestArchiveInterval:     getEnvDuration("REQUEST_ARCHIVE_INTERVAL", time.Hour),
+		RateLimitWindow:            getEnvDuration("RATE_LIMIT_WINDOW", 10*time.Minute),
+		RateLimitIPMax:             getEnvInt("RATE_LIMIT_IP_MAX", 30),
+		RateLimitTableRequestMax:   getEnvInt("RATE_LIMIT_TABLE_REQUEST_MAX", 5),
+		RateLimitTableVenueInfoMax: getEnvInt("RATE_LIMIT_TABLE_VENUE_INFO_MAX", 60),
+		TrustedProxies:             getEnvList("TRUSTED_PROXIES"),
+		QRLogoPath:                 getEnv("QR_LOGO_PATH", ""),
+		QRSigningSecret:            getEnv("QR_SIGNING_SECRET", jwtSecret),
+		LegacyQRAcceptedUntil:      legacyQRAcceptedUntil,
+		ShortLinkBaseURL:           getEnv("SHORT_LINK_BASE_URL", "http://localhost:8080"),
 	}, nil
 }
 
@@ -71,3 +103,63 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvList parses a comma-separated env var, dropping empty entries
+func getEnvList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvDuration parses a duration env var (e.g. "30m", "1h"), falling back to
+// the default when unset or invalid
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return defaultValue
+	}
+	return d
+}
+
+// getEnvDate parses a YYYY-MM-DD env var as the end of that day in UTC.
+// It returns the zero time when unset.
+func getEnvDate(key string) (time.Time, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q, expected YYYY-MM-DD", key, value)
+	}
+	return day.AddDate(0, 0, 1), nil
+}
+
+// getEnvInt parses an integer env var, falling back to the default when unset or invalid
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return defaultValue
+	}
+	return n
+}
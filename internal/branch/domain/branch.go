@@ -12,8 +12,14 @@ type Branch struct {
 	Address      string             `json:"address" bson:"address"`
 	Description  string             `json:"description,omitempty" bson:"description,omitempty"`
 	IsActive     bool               `json:"isActive" bson:"is_active"`
-	CreatedAt    time.Time          `json:"createdAt" bson:"created_at"`
-	UpdatedAt    time.Time          `json:"updatedAt" bson:"updated_at"`
+	// Timezone is the IANA zone the opening hours are in; empty means DefaultTimezone
+	Timezone string `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	// OpeningHours restricts when diners can send requests; nil means always open
+	OpeningHours *OpeningHours `json:"openingHours,omitempty" bson:"opening_hours,omitempty"`
+	// Geofence restricts public requests to diners near the branch; nil means disabled
+	Geofence  *Geofence `json:"geofence,omitempty" bson:"geofence,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updated_at"`
 }
 
 // CreateBranchInput represents the data needed to create a branch
@@ -30,6 +36,22 @@ type UpdateBranchInput struct {
 	IsActive    *bool  `json:"isActive,omitempty"`
 }
 
+// CloneBranchInput represents the data needed to clone a branch into a new location
+type CloneBranchInput struct {
+	Address string `json:"address" binding:"required,max=200"`
+	// Description defaults to the source branch's description
+	Description *string `json:"description,omitempty" binding:"omitempty,max=500"`
+}
+
+// CloneBranchResult is the branch created by a clone and what was copied into it
+type CloneBranchResult struct {
+	Branch         *Branch            `json:"branch"`
+	SourceBranchID primitive.ObjectID `json:"sourceBranchId"`
+	ZonesCopied    int                `json:"zonesCopied"`
+	TablesCopied   int                `json:"tablesCopied"`
+	SettingsCopied bool               `json:"settingsCopied"`
+}
+
 // NewBranch creates a new branch with the current timestamp
 func NewBranch(restaurantID primitive.ObjectID, address, description string) *Branch {
 	now := time.Now()
@@ -0,0 +1,55 @@
+package domain
+
+import "math"
+
+// Geofence modes
+const (
+	// GeofenceModeStrict rejects requests sent from outside the radius or without a location
+	GeofenceModeStrict = "strict"
+	// GeofenceModeLogOnly lets those requests through and only logs them, to tune the radius
+	GeofenceModeLogOnly = "log_only"
+)
+
+// earthRadiusMeters is the mean Earth radius used for distances between coordinates
+const earthRadiusMeters = 6371000
+
+// Geofence limits a branch's public requests to diners within RadiusMeters of
+// the branch's coordinates, as reported by the diner's browser geolocation
+type Geofence struct {
+	Latitude     float64 `json:"latitude" bson:"latitude"`
+	Longitude    float64 `json:"longitude" bson:"longitude"`
+	RadiusMeters int     `json:"radiusMeters" bson:"radius_meters"`
+	Mode         string  `json:"mode" bson:"mode"`
+}
+
+// GeofenceInput enables or replaces a branch's geofence
+type GeofenceInput struct {
+	Latitude     *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude    *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	RadiusMeters int      `json:"radiusMeters" binding:"required,min=10,max=10000"`
+	// Mode defaults to strict
+	Mode string `json:"mode,omitempty" binding:"omitempty,oneof=strict log_only"`
+}
+
+// DistanceMeters returns the great-circle distance from the geofence center to
+// the given coordinates, using the haversine formula
+func (g *Geofence) DistanceMeters(latitude, longitude float64) float64 {
+	lat1 := g.Latitude * math.Pi / 180
+	lat2 := latitude * math.Pi / 180
+	deltaLat := (latitude - g.Latitude) * math.Pi / 180
+	deltaLng := (longitude - g.Longitude) * math.Pi / 180
+
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLng/2)*math.Sin(deltaLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Contains reports whether the coordinates are within the geofence radius
+func (g *Geofence) Contains(latitude, longitude float64) bool {
+	return g.DistanceMeters(latitude, longitude) <= float64(g.RadiusMeters)
+}
+
+// IsStrict reports whether requests from outside the geofence are rejected
+func (g *Geofence) IsStrict() bool {
+	return g.Mode != GeofenceModeLogOnly
+}
@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultTimezone is used for branches that have opening hours but no timezone
+const DefaultTimezone = "America/Argentina/Buenos_Aires"
+
+// OpeningHours is a branch's weekly schedule plus date exceptions such as holidays.
+// Times are "HH:MM" in the branch timezone. A period whose close is not after its
+// open runs past midnight, so 20:00-02:00 on Friday also covers early Saturday.
+type OpeningHours struct {
+	Weekly     []OpeningPeriod  `json:"weekly" bson:"weekly"`
+	Exceptions []HoursException `json:"exceptions,omitempty" bson:"exceptions,omitempty"`
+}
+
+// OpeningPeriod is a time range the branch is open on a day of the week.
+// Day follows time.Weekday: 0 is Sunday.
+type OpeningPeriod struct {
+	Day   int    `json:"day" bson:"day"`
+	Open  string `json:"open" bson:"open"`
+	Close string `json:"close" bson:"close"`
+}
+
+// HoursException replaces the weekly schedule on one date. Without periods the
+// branch is closed all day.
+type HoursException struct {
+	Date    string      `json:"date" bson:"date"`
+	Name    string      `json:"name,omitempty" bson:"name,omitempty"`
+	Periods []TimeRange `json:"periods,omitempty" bson:"periods,omitempty"`
+}
+
+// TimeRange is an opening period within an exception date
+type TimeRange struct {
+	Open  string `json:"open" bson:"open"`
+	Close string `json:"close" bson:"close"`
+}
+
+// OpeningHoursInput replaces a branch's timezone and opening hours
+type OpeningHoursInput struct {
+	// Timezone is an IANA zone name; empty means DefaultTimezone
+	Timezone   string                `json:"timezone,omitempty" binding:"max=64"`
+	Weekly     []OpeningPeriodInput  `json:"weekly" binding:"max=50,dive"`
+	Exceptions []HoursExceptionInput `json:"exceptions,omitempty" binding:"max=100,dive"`
+}
+
+// OpeningPeriodInput is a weekly opening period
+type OpeningPeriodInput struct {
+	Day   int    `json:"day" binding:"min=0,max=6"`
+	Open  string `json:"open" binding:"required"`
+	Close string `json:"close" binding:"required"`
+}
+
+// HoursExceptionInput is a date exception; no periods means closed all day
+type HoursExceptionInput struct {
+	Date    string           `json:"date" binding:"required,datetime=2006-01-02"`
+	Name    string           `json:"name,omitempty" binding:"max=100"`
+	Periods []TimeRangeInput `json:"periods,omitempty" binding:"max=10,dive"`
+}
+
+// TimeRangeInput is an opening period within an exception date
+type TimeRangeInput struct {
+	Open  string `json:"open" binding:"required"`
+	Close string `json:"close" binding:"required"`
+}
+
+// ParseClock parses an "HH:MM" time into minutes after midnight. "24:00" is
+// accepted as a closing time.
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		if value == "24:00" {
+			return 24 * 60, nil
+		}
+		return 0, fmt.Errorf("%q is not a valid HH:MM time", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Location returns the branch's timezone, falling back to DefaultTimezone and then UTC
+func (b *Branch) Location() *time.Location {
+	name := b.Timezone
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// IsOpenAt reports whether the branch takes requests at t. Branches without
+// opening hours are always open.
+func (b *Branch) IsOpenAt(t time.Time) bool {
+	if b.OpeningHours == nil {
+		return true
+	}
+	return b.OpeningHours.isOpenAt(t.In(b.Location()))
+}
+
+// NextOpening returns when the closed branch opens next, looking up to two weeks
+// ahead. It returns nil when the branch is open at t or has no opening in that window.
+func (b *Branch) NextOpening(t time.Time) *time.Time {
+	if b.OpeningHours == nil || b.IsOpenAt(t) {
+		return nil
+	}
+
+	local := t.In(b.Location())
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	for i := 0; i < 15; i++ {
+		date := day.AddDate(0, 0, i)
+		for _, period := range b.OpeningHours.periodsOn(date) {
+			open := minutesInto(date, period.open)
+			if open.After(t) {
+				return &open
+			}
+		}
+	}
+	return nil
+}
+
+// clockRange is a parsed opening period in minutes after midnight
+type clockRange struct {
+	open, close int
+}
+
+// overnight reports whether the period ends on the next day
+func (r clockRange) overnight() bool {
+	return r.close <= r.open
+}
+
+// isOpenAt checks the periods of the local date and the overnight periods of the day before
+func (h *OpeningHours) isOpenAt(local time.Time) bool {
+	date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	minute := local.Hour()*60 + local.Minute()
+
+	for _, period := range h.periodsOn(date) {
+		if minute >= period.open && (period.overnight() || minute < period.close) {
+			return true
+		}
+	}
+	for _, period := range h.periodsOn(date.AddDate(0, 0, -1)) {
+		if period.overnight() && minute < period.close {
+			return true
+		}
+	}
+	return false
+}
+
+// periodsOn returns the opening periods starting on the date, sorted by opening time.
+// An exception for the date replaces the weekly schedule.
+func (h *OpeningHours) periodsOn(date time.Time) []clockRange {
+	key := date.Format("2006-01-02")
+	var periods []clockRange
+
+	exception := false
+	for _, e := range h.Exceptions {
+		if e.Date != key {
+			continue
+		}
+		exception = true
+		for _, p := range e.Periods {
+			periods = appendClockRange(periods, p.Open, p.Close)
+		}
+	}
+	if !exception {
+		for _, p := range h.Weekly {
+			if time.Weekday(p.Day) == date.Weekday() {
+				periods = appendClockRange(periods, p.Open, p.Close)
+			}
+		}
+	}
+
+	sort.Slice(periods, func(i, j int) bool { return periods[i].open < periods[j].open })
+	return periods
+}
+
+// appendClockRange parses a stored period, skipping it if it's malformed
+func appendClockRange(periods []clockRange, open, close string) []clockRange {
+	o, err := ParseClock(open)
+	if err != nil || o >= 24*60 {
+		return periods
+	}
+	c, err := ParseClock(close)
+	if err != nil {
+		return periods
+	}
+	return append(periods, clockRange{open: o, close: c})
+}
+
+// minutesInto returns the time the given minutes after the start of date
+func minutesInto(date time.Time, minutes int) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), minutes/60, minutes%60, 0, 0, date.Location())
+}
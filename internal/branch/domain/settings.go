@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Payment methods a branch can accept
+const (
+	PaymentCash          = "cash"
+	PaymentDebitCard     = "debit_card"
+	PaymentCreditCard    = "credit_card"
+	PaymentMercadoPagoQR = "mercado_pago_qr"
+	PaymentBankTransfer  = "bank_transfer"
+)
+
+// Request types diners can send
+const (
+	RequestTypeBill   = "bill"
+	RequestTypeWaiter = "waiter"
+)
+
+// Settings configures what diners can ask for at a branch. Branches without a
+// settings document use DefaultSettings.
+type Settings struct {
+	ID             primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	BranchID       primitive.ObjectID `json:"branchId" bson:"branch_id"`
+	PaymentMethods []string           `json:"paymentMethods" bson:"payment_methods"`
+	RequestTypes   []string           `json:"requestTypes" bson:"request_types"`
+	UpdatedAt      time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// UpdateSettingsInput replaces a branch's settings
+type UpdateSettingsInput struct {
+	PaymentMethods []string `json:"paymentMethods" binding:"required,min=1,unique,dive,oneof=cash debit_card credit_card mercado_pago_qr bank_transfer"`
+	RequestTypes   []string `json:"requestTypes" binding:"required,min=1,unique,dive,oneof=bill waiter"`
+}
+
+// DefaultSettings returns the settings of a branch that never configured them:
+// bill requests paid in cash, debit or credit card
+func DefaultSettings(branchID primitive.ObjectID) *Settings {
+	return &Settings{
+		BranchID:       branchID,
+		PaymentMethods: []string{PaymentCash, PaymentDebitCard, PaymentCreditCard},
+		RequestTypes:   []string{RequestTypeBill},
+	}
+}
+
+// AcceptsPaymentMethod reports whether diners can pay with the method
+func (s *Settings) AcceptsPaymentMethod(method string) bool {
+	return contains(s.PaymentMethods, method)
+}
+
+// AcceptsRequestType reports whether diners can send requests of the type
+func (s *Settings) AcceptsRequestType(requestType string) bool {
+	return contains(s.RequestTypes, requestType)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
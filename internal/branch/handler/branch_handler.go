@@ -203,6 +203,10 @@ func (h *Handler) Update(c *gin.Context) {
 			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
 			return
 		}
+		if errors.Is(err, pkg.ErrPlanLimitReached) {
+			pkg.ForbiddenResponse(c, "Your plan does not allow more active branches", err)
+			return
+		}
 		pkg.InternalServerErrorResponse(c, "Failed to update branch", err)
 		return
 	}
@@ -210,6 +214,54 @@ func (h *Handler) Update(c *gin.Context) {
 	pkg.SuccessResponse(c, http.StatusOK, "Branch updated successfully", branch)
 }
 
+// Clone handles creating a new branch as a copy of an existing one
+func (h *Handler) Clone(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchIDStr := c.Param("id")
+	branchID, err := primitive.ObjectIDFromHex(branchIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	var input domain.CloneBranchInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	result, err := h.useCase.Clone(c.Request.Context(), branchID, userID, input)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrPlanLimitReached) {
+			pkg.ForbiddenResponse(c, "Your plan does not allow another branch with these tables", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to clone branch", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusCreated, "Branch cloned successfully", result)
+}
+
 // Delete handles branch deletion
 func (h *Handler) Delete(c *gin.Context) {
 	userIDStr, exists := middleware.GetUserID(c)
@@ -247,12 +299,207 @@ func (h *Handler) Delete(c *gin.Context) {
 	pkg.SuccessResponse(c, http.StatusOK, "Branch deleted successfully", nil)
 }
 
+// UpdateOpeningHours handles replacing a branch's timezone and opening hours
+func (h *Handler) UpdateOpeningHours(c *gin.Context) {
+	var input domain.OpeningHoursInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	h.saveOpeningHours(c, &input, "Opening hours updated successfully")
+}
+
+// DeleteOpeningHours handles removing a branch's opening hours, so it takes requests at any time
+func (h *Handler) DeleteOpeningHours(c *gin.Context) {
+	h.saveOpeningHours(c, nil, "Opening hours removed successfully")
+}
+
+// saveOpeningHours replaces the branch's opening hours with input, or removes them when nil
+func (h *Handler) saveOpeningHours(c *gin.Context, input *domain.OpeningHoursInput, message string) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	branch, err := h.useCase.UpdateOpeningHours(c.Request.Context(), branchID, userID, input)
+	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to update opening hours", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, message, branch)
+}
+
+// UpdateGeofence handles enabling or replacing a branch's geofence
+func (h *Handler) UpdateGeofence(c *gin.Context) {
+	var input domain.GeofenceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	h.saveGeofence(c, &input, "Geofence updated successfully")
+}
+
+// DeleteGeofence handles disabling a branch's geofence
+func (h *Handler) DeleteGeofence(c *gin.Context) {
+	h.saveGeofence(c, nil, "Geofence removed successfully")
+}
+
+// saveGeofence replaces the branch's geofence with input, or disables it when nil
+func (h *Handler) saveGeofence(c *gin.Context, input *domain.GeofenceInput, message string) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	branch, err := h.useCase.UpdateGeofence(c.Request.Context(), branchID, userID, input)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to update geofence", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, message, branch)
+}
+
+// GetSettings handles retrieving the payment methods and request types a branch accepts
+func (h *Handler) GetSettings(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	settings, err := h.useCase.GetSettings(c.Request.Context(), branchID, userID, extractRestaurantIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to get branch settings", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Branch settings retrieved successfully", settings)
+}
+
+// UpdateSettings handles replacing the payment methods and request types a branch accepts
+func (h *Handler) UpdateSettings(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	var input domain.UpdateSettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	settings, err := h.useCase.UpdateSettings(c.Request.Context(), branchID, userID, input)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to update branch settings", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Branch settings updated successfully", settings)
+}
+
 // RegisterRoutes registers all branch routes.
 // Read routes are accessible by owners and employees; write routes are owner-only.
 func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 	branches := router.Group("/branches")
 	{
 		branches.GET("/:id", h.GetByID)
+		branches.GET("/:id/settings", h.GetSettings)
 		branches.GET("/restaurant/:restaurantId", h.ListByRestaurant)
 	}
 
@@ -261,6 +508,12 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 	{
 		ownerBranches.POST("", h.Create)
 		ownerBranches.PUT("/:id", h.Update)
+		ownerBranches.POST("/:id/clone", h.Clone)
 		ownerBranches.DELETE("/:id", h.Delete)
+		ownerBranches.PUT("/:id/opening-hours", h.UpdateOpeningHours)
+		ownerBranches.DELETE("/:id/opening-hours", h.DeleteOpeningHours)
+		ownerBranches.PUT("/:id/geofence", h.UpdateGeofence)
+		ownerBranches.DELETE("/:id/geofence", h.DeleteGeofence)
+		ownerBranches.PUT("/:id/settings", h.UpdateSettings)
 	}
 }
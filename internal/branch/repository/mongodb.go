@@ -78,10 +78,13 @@ func (r *mongoRepository) Update(ctx context.Context, branch *domain.Branch) err
 
 	update := bson.M{
 		"$set": bson.M{
-			"address":     branch.Address,
-			"description": branch.Description,
-			"is_active":   branch.IsActive,
-			"updated_at":  branch.UpdatedAt,
+			"address":       branch.Address,
+			"description":   branch.Description,
+			"is_active":     branch.IsActive,
+			"timezone":      branch.Timezone,
+			"opening_hours": branch.OpeningHours,
+			"geofence":      branch.Geofence,
+			"updated_at":    branch.UpdatedAt,
 		},
 	}
 
@@ -97,6 +100,29 @@ func (r *mongoRepository) Update(ctx context.Context, branch *domain.Branch) err
 	return nil
 }
 
+func (r *mongoRepository) SetActive(ctx context.Context, ids []primitive.ObjectID, active bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$set": bson.M{
+			"is_active":  active,
+			"updated_at": time.Now(),
+		},
+	}
+
+	_, err := r.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	return err
+}
+
+func (r *mongoRepository) Touch(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"updated_at": time.Now()}})
+	return err
+}
+
 func (r *mongoRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
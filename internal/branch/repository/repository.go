@@ -13,5 +13,18 @@ type Repository interface {
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Branch, error)
 	FindByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID) ([]*domain.Branch, error)
 	Update(ctx context.Context, branch *domain.Branch) error
+	// SetActive activates or deactivates the given branches
+	SetActive(ctx context.Context, ids []primitive.ObjectID, active bool) error
+	// Touch bumps the branch's updated_at. Inside a transaction it makes concurrent
+	// transactions that touch the same branch conflict, so one of them is retried.
+	Touch(ctx context.Context, id primitive.ObjectID) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 }
+
+// SettingsRepository defines the interface for branch settings persistence
+type SettingsRepository interface {
+	// FindByBranchID returns nil if the branch has no settings document
+	FindByBranchID(ctx context.Context, branchID primitive.ObjectID) (*domain.Settings, error)
+	// Upsert creates or replaces the settings of their branch
+	Upsert(ctx context.Context, settings *domain.Settings) error
+}
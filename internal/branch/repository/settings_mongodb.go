@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"juansecalvinio/tepidolacuenta/internal/branch/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoSettingsRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoSettingsRepository creates a new MongoDB repository for branch settings
+func NewMongoSettingsRepository(db *mongo.Database) SettingsRepository {
+	return &mongoSettingsRepository{
+		collection: db.Collection("branch_settings"),
+	}
+}
+
+func (r *mongoSettingsRepository) FindByBranchID(ctx context.Context, branchID primitive.ObjectID) (*domain.Settings, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var settings domain.Settings
+	err := r.collection.FindOne(ctx, bson.M{"branch_id": branchID}).Decode(&settings)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *mongoSettingsRepository) Upsert(ctx context.Context, settings *domain.Settings) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	settings.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"payment_methods": settings.PaymentMethods,
+			"request_types":   settings.RequestTypes,
+			"updated_at":      settings.UpdatedAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	return r.collection.FindOneAndUpdate(ctx, bson.M{"branch_id": settings.BranchID}, update, opts).Decode(settings)
+}
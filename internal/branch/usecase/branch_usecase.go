@@ -6,10 +6,13 @@ import (
 
 	"juansecalvinio/tepidolacuenta/internal/branch/domain"
 	"juansecalvinio/tepidolacuenta/internal/branch/repository"
+	"juansecalvinio/tepidolacuenta/internal/database"
 	"juansecalvinio/tepidolacuenta/internal/pkg"
 	restaurantRepo "juansecalvinio/tepidolacuenta/internal/restaurant/repository"
 	subscriptionRepo "juansecalvinio/tepidolacuenta/internal/subscription/repository"
 	subscriptionDomain "juansecalvinio/tepidolacuenta/internal/subscription/domain"
+	tableRepo "juansecalvinio/tepidolacuenta/internal/table/repository"
+	zoneRepo "juansecalvinio/tepidolacuenta/internal/zone/repository"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -21,27 +24,52 @@ type UseCase interface {
 	GetByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) ([]*domain.Branch, error)
 	Update(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.UpdateBranchInput) (*domain.Branch, error)
 	Delete(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) error
+	// UpdateOpeningHours replaces the branch's timezone and opening hours; nil input removes them
+	UpdateOpeningHours(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input *domain.OpeningHoursInput) (*domain.Branch, error)
+	// UpdateGeofence enables or replaces the branch's geofence; nil input disables it
+	UpdateGeofence(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input *domain.GeofenceInput) (*domain.Branch, error)
+	GetSettings(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) (*domain.Settings, error)
+	UpdateSettings(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.UpdateSettingsInput) (*domain.Settings, error)
+	Clone(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.CloneBranchInput) (*domain.CloneBranchResult, error)
 }
 
 type branchUseCase struct {
 	repo             repository.Repository
+	settingsRepo     repository.SettingsRepository
 	restaurantRepo   restaurantRepo.Repository
 	subscriptionRepo subscriptionRepo.SubscriptionRepository
 	planRepo         subscriptionRepo.PlanRepository
+	zoneRepo         zoneRepo.Repository
+	tableRepo        tableRepo.Repository
+	shortLinkRepo    tableRepo.ShortLinkRepository
+	tx               database.Transactor
+	qrService        *pkg.QRService
 }
 
 // NewBranchUseCase creates a new branch use case
 func NewBranchUseCase(
 	repo repository.Repository,
+	settingsRepo repository.SettingsRepository,
 	restaurantRepo restaurantRepo.Repository,
 	subscriptionRepo subscriptionRepo.SubscriptionRepository,
 	planRepo subscriptionRepo.PlanRepository,
+	zoneRepo zoneRepo.Repository,
+	tableRepo tableRepo.Repository,
+	shortLinkRepo tableRepo.ShortLinkRepository,
+	tx database.Transactor,
+	qrService *pkg.QRService,
 ) UseCase {
 	return &branchUseCase{
 		repo:             repo,
+		settingsRepo:     settingsRepo,
 		restaurantRepo:   restaurantRepo,
 		subscriptionRepo: subscriptionRepo,
 		planRepo:         planRepo,
+		zoneRepo:         zoneRepo,
+		tableRepo:        tableRepo,
+		shortLinkRepo:    shortLinkRepo,
+		tx:               tx,
+		qrService:        qrService,
 	}
 }
 
@@ -154,6 +182,11 @@ func (uc *branchUseCase) Update(ctx context.Context, id primitive.ObjectID, user
 	}
 
 	if input.IsActive != nil {
+		if *input.IsActive && !branch.IsActive {
+			if err := uc.checkBranchActivationLimit(ctx, branch.RestaurantID); err != nil {
+				return nil, err
+			}
+		}
 		branch.IsActive = *input.IsActive
 	}
 
@@ -188,30 +221,67 @@ func (uc *branchUseCase) Delete(ctx context.Context, id primitive.ObjectID, user
 
 // checkBranchPlanLimit verifies the restaurant's plan allows creating another branch
 func (uc *branchUseCase) checkBranchPlanLimit(ctx context.Context, restaurantID primitive.ObjectID) error {
-	subscription, err := uc.subscriptionRepo.FindByRestaurantID(ctx, restaurantID)
+	plan, err := uc.restaurantPlan(ctx, restaurantID)
 	if err != nil {
-		// No subscription found — block creation
+		return err
+	}
+
+	// Unlimited branches
+	if plan.MaxBranches == subscriptionDomain.Unlimited {
+		return nil
+	}
+
+	existing, err := uc.repo.FindByRestaurantID(ctx, restaurantID)
+	if err != nil {
+		return err
+	}
+
+	if len(existing) >= plan.MaxBranches {
 		return pkg.ErrPlanLimitReached
 	}
 
-	plan, err := uc.planRepo.FindByID(ctx, subscription.PlanID)
+	return nil
+}
+
+// checkBranchActivationLimit verifies the restaurant's plan allows one more active
+// branch. Branches can be over the limit after a plan downgrade, as long as the
+// extra ones stay inactive.
+func (uc *branchUseCase) checkBranchActivationLimit(ctx context.Context, restaurantID primitive.ObjectID) error {
+	plan, err := uc.restaurantPlan(ctx, restaurantID)
 	if err != nil {
 		return err
 	}
 
-	// Unlimited branches
 	if plan.MaxBranches == subscriptionDomain.Unlimited {
 		return nil
 	}
 
-	existing, err := uc.repo.FindByRestaurantID(ctx, restaurantID)
+	branches, err := uc.repo.FindByRestaurantID(ctx, restaurantID)
 	if err != nil {
 		return err
 	}
 
-	if len(existing) >= plan.MaxBranches {
+	active := 0
+	for _, branch := range branches {
+		if branch.IsActive {
+			active++
+		}
+	}
+	if active >= plan.MaxBranches {
 		return pkg.ErrPlanLimitReached
 	}
 
 	return nil
 }
+
+// restaurantPlan returns the plan of the restaurant's subscription.
+// Restaurants without a subscription get pkg.ErrPlanLimitReached.
+func (uc *branchUseCase) restaurantPlan(ctx context.Context, restaurantID primitive.ObjectID) (*subscriptionDomain.Plan, error) {
+	subscription, err := uc.subscriptionRepo.FindByRestaurantID(ctx, restaurantID)
+	if err != nil {
+		// No subscription found — block creation
+		return nil, pkg.ErrPlanLimitReached
+	}
+
+	return uc.planRepo.FindByID(ctx, subscription.PlanID)
+}
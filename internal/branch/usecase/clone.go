@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"juansecalvinio/tepidolacuenta/internal/branch/domain"
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	subscriptionDomain "juansecalvinio/tepidolacuenta/internal/subscription/domain"
+	tableDomain "juansecalvinio/tepidolacuenta/internal/table/domain"
+	zoneDomain "juansecalvinio/tepidolacuenta/internal/zone/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Clone creates a new branch of the same restaurant at another address, copying
+// the source branch's zones, tables, opening hours and settings. Copied tables get
+// new tokens and QR codes, so stickers printed for the source branch keep pointing
+// at it. Everything is written in a single transaction.
+func (uc *branchUseCase) Clone(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.CloneBranchInput) (*domain.CloneBranchResult, error) {
+	source, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, source.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if restaurant.UserID != userID {
+		return nil, pkg.ErrUnauthorized
+	}
+
+	// Validate plan branch limit
+	if err := uc.checkBranchPlanLimit(ctx, restaurant.ID); err != nil {
+		return nil, err
+	}
+
+	zones, err := uc.zoneRepo.FindByBranchID(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := uc.tableRepo.FindByBranchID(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The source may be over the table limit after a plan downgrade; the copy can't be
+	plan, err := uc.restaurantPlan(ctx, restaurant.ID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.MaxTables != subscriptionDomain.Unlimited && len(tables) > plan.MaxTables {
+		return nil, pkg.ErrPlanLimitReached
+	}
+
+	settings, err := uc.settingsRepo.FindByBranchID(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	description := source.Description
+	if input.Description != nil {
+		description = *input.Description
+	}
+	branch := domain.NewBranch(restaurant.ID, input.Address, description)
+	branch.Timezone = source.Timezone
+	branch.OpeningHours = source.OpeningHours
+
+	result := &domain.CloneBranchResult{
+		Branch:         branch,
+		SourceBranchID: source.ID,
+	}
+
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.Create(ctx, branch); err != nil {
+			return fmt.Errorf("failed to create branch: %w", err)
+		}
+
+		// Zones are created first so the copied tables can point at them
+		zoneIDs := make(map[primitive.ObjectID]primitive.ObjectID, len(zones))
+		for _, zone := range zones {
+			newZone := zoneDomain.NewZone(branch.ID, zone.Name)
+			if err := uc.zoneRepo.Create(ctx, newZone); err != nil {
+				return fmt.Errorf("failed to create zone %q: %w", zone.Name, err)
+			}
+			zoneIDs[zone.ID] = newZone.ID
+		}
+
+		if len(tables) > 0 {
+			newTables := make([]*tableDomain.Table, 0, len(tables))
+			links := make([]*tableDomain.ShortLink, 0, len(tables))
+			for _, table := range tables {
+				newTable := tableDomain.NewTableWithQR(uc.qrService, branch.ID, table.Number)
+				newTable.Label = table.Label
+				newTable.Capacity = table.Capacity
+				newTable.Priority = table.Priority
+				newTable.IsActive = table.IsActive
+				newTable.Layout = table.Layout
+				if table.ZoneID != nil {
+					if zoneID, ok := zoneIDs[*table.ZoneID]; ok {
+						newTable.ZoneID = &zoneID
+					}
+				}
+
+				link := tableDomain.NewTableShortLink(uc.qrService, newTable)
+
+				newTables = append(newTables, newTable)
+				links = append(links, link)
+			}
+
+			if err := uc.shortLinkRepo.CreateMany(ctx, links); err != nil {
+				return fmt.Errorf("failed to create short links: %w", err)
+			}
+			if err := uc.tableRepo.CreateMany(ctx, newTables); err != nil {
+				return fmt.Errorf("failed to create tables: %w", err)
+			}
+		}
+
+		if settings != nil {
+			copied := &domain.Settings{
+				BranchID:       branch.ID,
+				PaymentMethods: settings.PaymentMethods,
+				RequestTypes:   settings.RequestTypes,
+			}
+			if err := uc.settingsRepo.Upsert(ctx, copied); err != nil {
+				return fmt.Errorf("failed to copy settings: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.ZonesCopied = len(zones)
+	result.TablesCopied = len(tables)
+	result.SettingsCopied = settings != nil
+	return result, nil
+}
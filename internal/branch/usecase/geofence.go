@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+
+	"juansecalvinio/tepidolacuenta/internal/branch/domain"
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UpdateGeofence enables or replaces the branch's geofence. A nil input disables
+// it, so public requests are accepted from anywhere again.
+func (uc *branchUseCase) UpdateGeofence(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input *domain.GeofenceInput) (*domain.Branch, error) {
+	branch, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, branch.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if restaurant.UserID != userID {
+		return nil, pkg.ErrUnauthorized
+	}
+
+	branch.Geofence = nil
+	if input != nil {
+		mode := input.Mode
+		if mode == "" {
+			mode = domain.GeofenceModeStrict
+		}
+		branch.Geofence = &domain.Geofence{
+			Latitude:     *input.Latitude,
+			Longitude:    *input.Longitude,
+			RadiusMeters: input.RadiusMeters,
+			Mode:         mode,
+		}
+	}
+
+	if err := uc.repo.Update(ctx, branch); err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
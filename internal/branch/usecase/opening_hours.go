@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"juansecalvinio/tepidolacuenta/internal/branch/domain"
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UpdateOpeningHours replaces the branch's timezone and opening hours. A nil
+// input removes them, so the branch takes requests at any time again.
+func (uc *branchUseCase) UpdateOpeningHours(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input *domain.OpeningHoursInput) (*domain.Branch, error) {
+	branch, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, branch.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if restaurant.UserID != userID {
+		return nil, pkg.ErrUnauthorized
+	}
+
+	branch.Timezone = ""
+	branch.OpeningHours = nil
+	if input != nil {
+		if input.Timezone != "" {
+			if _, err := time.LoadLocation(input.Timezone); err != nil {
+				return nil, fmt.Errorf("%w: unknown timezone %q", pkg.ErrInvalidInput, input.Timezone)
+			}
+		}
+		hours, err := buildOpeningHours(input)
+		if err != nil {
+			return nil, err
+		}
+		branch.Timezone = input.Timezone
+		branch.OpeningHours = hours
+	}
+
+	if err := uc.repo.Update(ctx, branch); err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+// buildOpeningHours validates the opening hours input. Periods can't open at
+// 24:00, and each exception date can only appear once.
+func buildOpeningHours(input *domain.OpeningHoursInput) (*domain.OpeningHours, error) {
+	hours := &domain.OpeningHours{
+		Weekly:     make([]domain.OpeningPeriod, 0, len(input.Weekly)),
+		Exceptions: make([]domain.HoursException, 0, len(input.Exceptions)),
+	}
+
+	for _, period := range input.Weekly {
+		if err := validateTimeRange(period.Open, period.Close); err != nil {
+			return nil, err
+		}
+		hours.Weekly = append(hours.Weekly, domain.OpeningPeriod{
+			Day:   period.Day,
+			Open:  period.Open,
+			Close: period.Close,
+		})
+	}
+
+	dates := make(map[string]bool, len(input.Exceptions))
+	for _, exception := range input.Exceptions {
+		if dates[exception.Date] {
+			return nil, fmt.Errorf("%w: the date %s has more than one exception", pkg.ErrInvalidInput, exception.Date)
+		}
+		dates[exception.Date] = true
+
+		periods := make([]domain.TimeRange, 0, len(exception.Periods))
+		for _, period := range exception.Periods {
+			if err := validateTimeRange(period.Open, period.Close); err != nil {
+				return nil, err
+			}
+			periods = append(periods, domain.TimeRange{Open: period.Open, Close: period.Close})
+		}
+		hours.Exceptions = append(hours.Exceptions, domain.HoursException{
+			Date:    exception.Date,
+			Name:    exception.Name,
+			Periods: periods,
+		})
+	}
+
+	return hours, nil
+}
+
+// validateTimeRange checks the open and close times of a period
+func validateTimeRange(open, close string) error {
+	openMinutes, err := domain.ParseClock(open)
+	if err != nil {
+		return fmt.Errorf("%w: %v", pkg.ErrInvalidInput, err)
+	}
+	if openMinutes >= 24*60 {
+		return fmt.Errorf("%w: periods can't open at 24:00", pkg.ErrInvalidInput)
+	}
+	if _, err := domain.ParseClock(close); err != nil {
+		return fmt.Errorf("%w: %v", pkg.ErrInvalidInput, err)
+	}
+	return nil
+}
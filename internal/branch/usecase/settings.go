@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"juansecalvinio/tepidolacuenta/internal/branch/domain"
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetSettings returns the payment methods and request types the branch accepts,
+// or the defaults if it never configured them
+func (uc *branchUseCase) GetSettings(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) (*domain.Settings, error) {
+	branch, err := uc.GetByID(ctx, id, userID, restaurantIDHint)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := uc.settingsRepo.FindByBranchID(ctx, branch.ID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return domain.DefaultSettings(branch.ID), nil
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings replaces the payment methods and request types the branch accepts
+func (uc *branchUseCase) UpdateSettings(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.UpdateSettingsInput) (*domain.Settings, error) {
+	branch, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, branch.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if restaurant.UserID != userID {
+		return nil, pkg.ErrUnauthorized
+	}
+
+	settings := &domain.Settings{
+		BranchID:       branch.ID,
+		PaymentMethods: input.PaymentMethods,
+		RequestTypes:   input.RequestTypes,
+	}
+	if err := uc.settingsRepo.Upsert(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
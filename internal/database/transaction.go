@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transactor runs work inside a MongoDB transaction
+type Transactor interface {
+	// WithTransaction runs fn in a transaction, committing when it returns nil and
+	// aborting otherwise. Repository calls made with the ctx passed to fn join the
+	// transaction. fn may be retried on transient errors, so it must only write.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// WithTransaction implements Transactor. Transactions need a replica set or a
+// sharded cluster, which is what MongoDB Atlas provides.
+func (m *MongoDB) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := m.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitByIP rejects callers that exceed the limiter for this route.
+// Each route is counted separately so venue-info lookups don't use up request creations.
+func RateLimitByIP(limiter *pkg.SlidingWindowLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limiter.Allow(c.FullPath() + "|" + c.ClientIP()) {
+			pkg.ErrorResponse(c, http.StatusTooManyRequests, "Too many requests, please try again later", pkg.ErrTooManyRequests)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
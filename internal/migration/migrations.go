@@ -4,15 +4,18 @@ import (
 	"context"
 	"time"
 
+	"juansecalvinio/tepidolacuenta/internal/pkg"
 	"juansecalvinio/tepidolacuenta/internal/subscription/domain"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// All returns the ordered list of migrations to apply
-func All() []Migration {
+// All returns the ordered list of migrations to apply.
+// qrService issues the table tokens backfilled by 015.
+func All(qrService *pkg.QRService) []Migration {
 	return []Migration{
 		{
 			Name: "001_update_plan_values",
@@ -62,7 +65,286 @@ func All() []Migration {
 			Name: "012_reset_all_data",
 			Run:  resetAllData,
 		},
+		{
+			Name: "013_create_request_retention_indexes",
+			Run:  createRequestRetentionIndexes,
+		},
+		{
+			Name: "014_create_pending_priority_indexes",
+			Run:  createPendingPriorityIndexes,
+		},
+		{
+			Name: "015_backfill_table_tokens",
+			Run: func(ctx context.Context, db *mongo.Database) error {
+				return backfillTableTokens(ctx, db, qrService)
+			},
+		},
+		{
+			Name: "016_backfill_table_short_links",
+			Run: func(ctx context.Context, db *mongo.Database) error {
+				return backfillTableShortLinks(ctx, db, qrService)
+			},
+		},
+		{
+			Name: "017_create_table_scans_indexes",
+			Run:  createTableScansIndexes,
+		},
+		{
+			Name: "018_create_zones_indexes",
+			Run:  createZonesIndexes,
+		},
+		{
+			Name: "019_create_table_labels_index",
+			Run:  createTableLabelsIndex,
+		},
+		{
+			Name: "020_create_table_sessions_indexes",
+			Run:  createTableSessionsIndexes,
+		},
+		{
+			Name: "021_create_branch_settings_index",
+			Run:  createBranchSettingsIndex,
+		},
+		{
+			Name: "022_set_type_on_existing_requests",
+			Run:  setTypeOnExistingRequests,
+		},
+		{
+			Name: "023_set_type_on_archived_requests",
+			Run:  setTypeOnArchivedRequests,
+		},
+	}
+}
+
+// createBranchSettingsIndex allows a single settings document per branch
+func createBranchSettingsIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("branch_settings").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "branch_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// setTypeOnExistingRequests marks the requests made before request types as bill requests
+func setTypeOnExistingRequests(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("requests").UpdateMany(
+		ctx,
+		bson.M{"type": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"type": "bill"}},
+	)
+	return err
+}
+
+// setTypeOnArchivedRequests marks the requests archived before request types as bill requests
+func setTypeOnArchivedRequests(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("requests_archive").UpdateMany(
+		ctx,
+		bson.M{"type": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"type": "bill"}},
+	)
+	return err
+}
+
+// createTableSessionsIndexes allows a single open session per table and backs
+// the branch session listings and metrics
+func createTableSessionsIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("table_sessions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "table_id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": "open"}),
+		},
+		{
+			Keys: bson.D{{Key: "branch_id", Value: 1}, {Key: "opened_at", Value: -1}},
+		},
+	})
+	return err
+}
+
+// createTableLabelsIndex keeps table labels unique per branch.
+// Tables without a label are left out of the index.
+func createTableLabelsIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("tables").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "branch_id", Value: 1}, {Key: "label", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"label": bson.M{"$gt": ""}}),
+	})
+	return err
+}
+
+// createZonesIndexes keeps zone names unique per branch and backs the lookup
+// of a zone's tables when the zone is deleted
+func createZonesIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("zones").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "branch_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("tables").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "zone_id", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+	return err
+}
+
+// createTableScansIndexes backs the hourly scan bucket upserts and the scan report
+func createTableScansIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("table_scans").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tableId", Value: 1}, {Key: "hour", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "restaurantId", Value: 1}, {Key: "hour", Value: 1}},
+		},
+	})
+	return err
+}
+
+// backfillTableShortLinks creates the unique slug index and issues a short link
+// for the current QR version of every table that doesn't have one yet
+func backfillTableShortLinks(ctx context.Context, db *mongo.Database, qrService *pkg.QRService) error {
+	tables := db.Collection("tables")
+	links := db.Collection("table_short_links")
+
+	_, err := links.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	cursor, err := tables.Find(ctx,
+		bson.M{"slug": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"_id": 1, "token": 1, "qr_version": 1}),
+	)
+	if err != nil {
+		return err
 	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var table struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Token     string             `bson:"token"`
+			QRVersion int                `bson:"qr_version"`
+		}
+		if err := cursor.Decode(&table); err != nil {
+			return err
+		}
+
+		slug := qrService.GenerateSlug()
+		for {
+			_, err := links.InsertOne(ctx, bson.M{
+				"slug":        slug,
+				"table_token": table.Token,
+				"qr_version":  table.QRVersion,
+				"scan_count":  0,
+				"created_at":  time.Now(),
+			})
+			if err == nil {
+				break
+			}
+			if !mongo.IsDuplicateKeyError(err) {
+				return err
+			}
+			slug = qrService.GenerateSlug()
+		}
+
+		_, err := tables.UpdateOne(ctx, bson.M{"_id": table.ID}, bson.M{"$set": bson.M{
+			"slug":       slug,
+			"short_url":  qrService.ShortLinkURL(slug),
+			"updated_at": time.Now(),
+		}})
+		if err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// backfillTableTokens gives every table without a token one and switches its
+// qr_code to the token URL. Stickers printed with the old URL keep working
+// through the legacy QR validation until LEGACY_QR_ACCEPTED_UNTIL, if one is
+// set. The unique index skips tables without a
+// token, so it can be created before or after the backfill.
+func backfillTableTokens(ctx context.Context, db *mongo.Database, qrService *pkg.QRService) error {
+	tables := db.Collection("tables")
+
+	_, err := tables.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "token", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"token": bson.M{"$type": "string"}}),
+	})
+	if err != nil {
+		return err
+	}
+
+	cursor, err := tables.Find(ctx, bson.M{"token": bson.M{"$exists": false}}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var table struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&table); err != nil {
+			return err
+		}
+
+		token := qrService.GenerateTableToken()
+		_, err := tables.UpdateOne(ctx, bson.M{"_id": table.ID}, bson.M{"$set": bson.M{
+			"token":      token,
+			"qr_code":    qrService.GenerateTableQRCode(token, 0),
+			"updated_at": time.Now(),
+		}})
+		if err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// createPendingPriorityIndexes backs the pending lists, sorted by priority then age
+func createPendingPriorityIndexes(ctx context.Context, db *mongo.Database) error {
+	requests := db.Collection("requests")
+	for _, scope := range []string{"restaurantId", "branchId"} {
+		index := mongo.IndexModel{
+			Keys: bson.D{
+				{Key: scope, Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "priority", Value: -1},
+				{Key: "createdAt", Value: 1},
+			},
+		}
+		if _, err := requests.Indexes().CreateOne(ctx, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createRequestRetentionIndexes indexes requests and requests_archive by restaurant
+// and creation date, used by the archival job and the stats aggregation
+func createRequestRetentionIndexes(ctx context.Context, db *mongo.Database) error {
+	index := mongo.IndexModel{
+		Keys: bson.D{{Key: "restaurantId", Value: 1}, {Key: "createdAt", Value: 1}},
+	}
+	for _, name := range []string{"requests", "requests_archive"} {
+		if _, err := db.Collection(name).Indexes().CreateOne(ctx, index); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // updatePlanPrices updates only the price field of existing plans
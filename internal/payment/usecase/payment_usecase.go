@@ -10,8 +10,8 @@ import (
 	"juansecalvinio/tepidolacuenta/internal/payment/repository"
 	"juansecalvinio/tepidolacuenta/internal/pkg"
 	restaurantRepo "juansecalvinio/tepidolacuenta/internal/restaurant/repository"
-	subscriptionDomain "juansecalvinio/tepidolacuenta/internal/subscription/domain"
 	subscriptionRepo "juansecalvinio/tepidolacuenta/internal/subscription/repository"
+	subscriptionUseCase "juansecalvinio/tepidolacuenta/internal/subscription/usecase"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -32,20 +32,20 @@ type CreatePreferenceResult struct {
 type NotifyFunc func(restaurantID primitive.ObjectID, event *domain.PaymentEvent)
 
 type paymentUseCase struct {
-	paymentRepo      repository.Repository
-	planRepo         subscriptionRepo.PlanRepository
-	subscriptionRepo subscriptionRepo.SubscriptionRepository
-	restaurantRepo   restaurantRepo.Repository
-	mp               *mpClient.Client
-	notificationURL  string
-	frontendURL      string
-	notify           NotifyFunc
+	paymentRepo     repository.Repository
+	planRepo        subscriptionRepo.PlanRepository
+	subscriptionUC  subscriptionUseCase.UseCase
+	restaurantRepo  restaurantRepo.Repository
+	mp              *mpClient.Client
+	notificationURL string
+	frontendURL     string
+	notify          NotifyFunc
 }
 
 func NewPaymentUseCase(
 	paymentRepo repository.Repository,
 	planRepo subscriptionRepo.PlanRepository,
-	subscriptionRepo subscriptionRepo.SubscriptionRepository,
+	subscriptionUC subscriptionUseCase.UseCase,
 	restaurantRepo restaurantRepo.Repository,
 	mp *mpClient.Client,
 	notificationURL string,
@@ -53,14 +53,14 @@ func NewPaymentUseCase(
 	notify NotifyFunc,
 ) UseCase {
 	return &paymentUseCase{
-		paymentRepo:      paymentRepo,
-		planRepo:         planRepo,
-		subscriptionRepo: subscriptionRepo,
-		restaurantRepo:   restaurantRepo,
-		mp:               mp,
-		notificationURL:  notificationURL,
-		frontendURL:      frontendURL,
-		notify:           notify,
+		paymentRepo:     paymentRepo,
+		planRepo:        planRepo,
+		subscriptionUC:  subscriptionUC,
+		restaurantRepo:  restaurantRepo,
+		mp:              mp,
+		notificationURL: notificationURL,
+		frontendURL:     frontendURL,
+		notify:          notify,
 	}
 }
 
@@ -255,29 +255,10 @@ func (uc *paymentUseCase) GetPaymentHistory(ctx context.Context, userID primitiv
 	return uc.paymentRepo.FindByRestaurantID(ctx, restaurantID)
 }
 
-// activateSubscription creates a new active subscription after a successful payment
+// activateSubscription puts the restaurant on the paid plan. Plan limits are
+// reconciled by the subscription use case.
 func (uc *paymentUseCase) activateSubscription(ctx context.Context, payment *domain.Payment) error {
-	// Check if an active subscription already exists (idempotency)
-	existing, err := uc.subscriptionRepo.FindByRestaurantID(ctx, payment.RestaurantID)
-	if err == nil && existing != nil &&
-		existing.Status != subscriptionDomain.SubscriptionStatusCanceled &&
-		existing.Status != subscriptionDomain.SubscriptionStatusExpired {
-		// Already active — update plan and payment ref
-		existing.PlanID = payment.PlanID
-		existing.Status = subscriptionDomain.SubscriptionStatusActive
-		existing.PaymentSubscriptionID = payment.MPPaymentID
-		return uc.subscriptionRepo.Update(ctx, existing)
-	}
-
-	subscription := subscriptionDomain.NewSubscription(
-		payment.UserID,
-		payment.RestaurantID,
-		payment.PlanID,
-		subscriptionDomain.SubscriptionStatusActive,
-	)
-	subscription.PaymentSubscriptionID = payment.MPPaymentID
-
-	return uc.subscriptionRepo.Create(ctx, subscription)
+	return uc.subscriptionUC.ActivatePaidPlan(ctx, payment.UserID, payment.RestaurantID, payment.PlanID, payment.MPPaymentID)
 }
 
 func parsePaymentID(s string) (int64, error) {
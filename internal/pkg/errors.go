@@ -16,4 +16,15 @@ var (
 	ErrSubscriptionAlreadyExists = errors.New("restaurant already has an active subscription")
 	ErrPlanLimitReached          = errors.New("plan limit reached")
 	ErrForbidden                 = errors.New("forbidden")
+	ErrRequestNotPending         = errors.New("request is not pending")
+	ErrTooManyRequests           = errors.New("too many requests")
+	ErrTableBlocked              = errors.New("requests from this table are temporarily blocked")
+	ErrInvalidQRCode             = errors.New("invalid QR code")
+	ErrQRCodeRevoked             = errors.New("this QR code has been replaced, please scan the current code on the table")
+	ErrZoneNameTaken             = errors.New("the branch already has a zone with this name")
+	ErrSessionAlreadyOpen        = errors.New("the table already has an open session")
+	ErrSessionClosed             = errors.New("session is already closed")
+	ErrBranchClosed              = errors.New("the venue is closed right now")
+	ErrPlanOverage               = errors.New("the restaurant has more active branches or tables than the plan allows")
+	ErrOutsideGeofence           = errors.New("requests can only be sent from the venue")
 )
@@ -0,0 +1,82 @@
+// Package ndef builds NFC Data Exchange Format messages, the binary payload
+// NFC writer apps store on tags (NFC Forum NDEF 1.0 and URI RTD).
+package ndef
+
+import "encoding/binary"
+
+// Record header flags and type name formats
+const (
+	flagMB         = 0x80 // Message begin
+	flagME         = 0x40 // Message end
+	flagSR         = 0x10 // Short record: 1-byte payload length
+	tnfWellKnown   = 0x01
+	shortRecordMax = 255
+)
+
+// uriPrefixes are the abbreviations defined by the URI record type, indexed by
+// their identifier code. Code 0 means no abbreviation.
+var uriPrefixes = []string{
+	"",
+	"http://www.",
+	"https://www.",
+	"http://",
+	"https://",
+	"tel:",
+	"mailto:",
+	"ftp://anonymous:anonymous@",
+	"ftp://ftp.",
+	"ftps://",
+	"sftp://",
+	"smb://",
+	"nfs://",
+	"ftp://",
+	"dav://",
+	"news:",
+	"telnet://",
+	"imap:",
+	"rtsp://",
+	"urn:",
+	"pop:",
+	"sip:",
+	"sips:",
+	"tftp:",
+	"btspp://",
+	"btl2cap://",
+	"btgoep://",
+	"tcpobex://",
+	"irdaobex://",
+	"file://",
+	"urn:epc:id:",
+	"urn:epc:tag:",
+	"urn:epc:pat:",
+	"urn:epc:raw:",
+	"urn:epc:",
+	"urn:nfc:",
+}
+
+// URIMessage returns an NDEF message holding a single URI record for uri.
+// The longest matching prefix is abbreviated to its one-byte code.
+func URIMessage(uri string) []byte {
+	code := 0
+	for i, prefix := range uriPrefixes {
+		if len(prefix) > len(uriPrefixes[code]) && len(uri) >= len(prefix) && uri[:len(prefix)] == prefix {
+			code = i
+		}
+	}
+	payload := append([]byte{byte(code)}, uri[len(uriPrefixes[code]):]...)
+	return record(tnfWellKnown, []byte("U"), payload)
+}
+
+// record encodes a record that is both the first and last of its message
+func record(tnf byte, recordType, payload []byte) []byte {
+	header := flagMB | flagME | tnf
+	out := make([]byte, 0, 6+len(recordType)+len(payload))
+	if len(payload) <= shortRecordMax {
+		out = append(out, header|flagSR, byte(len(recordType)), byte(len(payload)))
+	} else {
+		out = append(out, header, byte(len(recordType)))
+		out = binary.BigEndian.AppendUint32(out, uint32(len(payload)))
+	}
+	out = append(out, recordType...)
+	return append(out, payload...)
+}
@@ -0,0 +1,194 @@
+// Package pdf is a minimal PDF 1.4 writer for print sheets: vector shapes and
+// single-line text in the standard Helvetica fonts, which every viewer ships,
+// so no fonts need to be embedded.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Page sizes in points (1/72 inch)
+var (
+	A4     = Size{Width: 595.28, Height: 841.89}
+	Letter = Size{Width: 612, Height: 792}
+)
+
+// Size is a page size in points
+type Size struct {
+	Width  float64
+	Height float64
+}
+
+// Font is one of the standard Type 1 fonts supported by the writer
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+func (f Font) resourceName() string {
+	return [...]string{"F1", "F2"}[f]
+}
+
+func (f Font) baseFont() string {
+	return [...]string{"Helvetica", "Helvetica-Bold"}[f]
+}
+
+// Document is a PDF being built in memory
+type Document struct {
+	size  Size
+	pages []*Page
+}
+
+// Page is a page of a Document. Coordinates are in points from the bottom-left corner.
+type Page struct {
+	content strings.Builder
+}
+
+// New creates an empty document with pages of the given size
+func New(size Size) *Document {
+	return &Document{size: size}
+}
+
+// Size returns the page size of the document
+func (d *Document) Size() Size {
+	return d.size
+}
+
+// AddPage appends a blank page
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// FillRect draws a filled black rectangle
+func (p *Page) FillRect(x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%s %s %s %s re f\n", num(x), num(y), num(w), num(h))
+}
+
+// Line draws a black line of the given width
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// Text draws a single line of text with its baseline starting at (x, y).
+// Characters outside WinAnsi (roughly Latin-1) are replaced with '?'.
+func (p *Page) Text(x, y float64, font Font, size float64, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font.resourceName(), num(size), num(x), num(y), escape(encodeWinAnsi(text)))
+}
+
+// Bytes serializes the document
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	// Objects are numbered from 1: catalog, page tree, the two fonts, then a page and
+	// its content stream for every page
+	startObject := func() int {
+		offsets = append(offsets, buf.Len())
+		n := len(offsets)
+		fmt.Fprintf(&buf, "%d 0 obj\n", n)
+		return n
+	}
+	endObject := func() {
+		buf.WriteString("endobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	startObject()
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\n")
+	endObject()
+
+	const firstPageObject = 5
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObject+2*i)
+	}
+	startObject()
+	fmt.Fprintf(&buf, "<< /Type /Pages /Kids [%s] /Count %d >>\n", strings.Join(kids, " "), len(d.pages))
+	endObject()
+
+	for _, font := range []Font{Helvetica, HelveticaBold} {
+		startObject()
+		fmt.Fprintf(&buf, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\n", font.baseFont())
+		endObject()
+	}
+
+	for _, page := range d.pages {
+		n := startObject()
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>\n",
+			num(d.size.Width), num(d.size.Height), n+1)
+		endObject()
+
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		if _, err := zw.Write([]byte(page.content.String())); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+
+		startObject()
+		fmt.Fprintf(&buf, "<< /Length %d /Filter /FlateDecode >>\nstream\n", stream.Len())
+		buf.Write(stream.Bytes())
+		buf.WriteString("\nendstream\n")
+		endObject()
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes(), nil
+}
+
+// num formats a coordinate with at most two decimals
+func num(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// encodeWinAnsi maps text to single-byte WinAnsi codes. Latin-1 letters
+// (accents, ñ, ¿, ¡) share their code points with WinAnsi.
+func encodeWinAnsi(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 0x20 && r <= 0x7E, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		case r == '€':
+			out = append(out, 0x80)
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c == '(' || c == ')' || c == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
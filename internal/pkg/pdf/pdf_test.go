@@ -0,0 +1,196 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// sampleSheet builds a multi-page document shaped like a QR sheet: crop marks,
+// runs of filled modules and labels that need escaping and WinAnsi encoding
+func sampleSheet(pages int) *Document {
+	doc := New(A4)
+	for i := 0; i < pages; i++ {
+		page := doc.AddPage()
+		page.Line(36, 811.89, 36, 829.89, 0.25)
+		for y := 0; y < 21; y++ {
+			page.FillRect(60+float64(y%3)*4.5, 700-float64(y)*4.5, 13.5, 4.5)
+		}
+		page.Text(80, 600, HelveticaBold, 21.6, fmt.Sprintf("Mesa %d", i+1))
+		page.Text(80, 580, Helvetica, 12, "Café (Palermo) \\ Año 2024 – 10€")
+	}
+	return doc
+}
+
+var objectHeader = regexp.MustCompile(`^(\d+) 0 obj\n`)
+
+// parseXref reads the cross-reference table that startxref points at and returns
+// the offset of every in-use object, indexed by object number
+func parseXref(t *testing.T, data []byte) []int {
+	t.Helper()
+
+	tail := data[bytes.LastIndex(data, []byte("startxref\n")):]
+	var start int
+	if _, err := fmt.Sscanf(string(tail), "startxref\n%d\n%%%%EOF\n", &start); err != nil {
+		t.Fatalf("malformed trailer %q: %v", tail, err)
+	}
+	if want := bytes.LastIndex(data, []byte("xref\n0 ")); start != want {
+		t.Fatalf("startxref = %d, xref table is at %d", start, want)
+	}
+
+	lines := strings.Split(string(data[start:]), "\n")
+	if lines[0] != "xref" {
+		t.Fatalf("startxref points at %q, want xref", lines[0])
+	}
+	var first, count int
+	if _, err := fmt.Sscanf(lines[1], "%d %d", &first, &count); err != nil || first != 0 {
+		t.Fatalf("malformed xref subsection %q", lines[1])
+	}
+	if lines[2] != "0000000000 65535 f " {
+		t.Fatalf("object 0 entry = %q", lines[2])
+	}
+
+	offsets := make([]int, count)
+	for n := 1; n < count; n++ {
+		entry := lines[2+n]
+		// Entries are exactly 20 bytes including the end-of-line
+		if len(entry) != 19 || !strings.HasSuffix(entry, " 00000 n ") {
+			t.Fatalf("malformed xref entry for object %d: %q", n, entry)
+		}
+		offset, err := strconv.Atoi(entry[:10])
+		if err != nil {
+			t.Fatalf("malformed offset for object %d: %q", n, entry)
+		}
+		offsets[n] = offset
+	}
+
+	if !strings.HasPrefix(lines[2+count], "trailer") {
+		t.Fatalf("expected trailer after %d xref entries, got %q", count, lines[2+count])
+	}
+	if want := fmt.Sprintf("/Size %d ", count); !bytes.Contains(data[start:], []byte(want)) {
+		t.Errorf("trailer doesn't declare %s", want)
+	}
+	return offsets
+}
+
+func TestXrefOffsets(t *testing.T) {
+	for _, pages := range []int{0, 1, 3} {
+		t.Run(fmt.Sprintf("%d pages", pages), func(t *testing.T) {
+			data, err := sampleSheet(pages).Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) {
+				t.Fatalf("missing header: %q", data[:16])
+			}
+
+			offsets := parseXref(t, data)
+			if want := 1 + 4 + 2*pages; len(offsets) != want {
+				t.Fatalf("xref has %d entries, want %d", len(offsets), want)
+			}
+
+			for n := 1; n < len(offsets); n++ {
+				m := objectHeader.FindSubmatch(data[offsets[n]:])
+				if m == nil {
+					t.Fatalf("offset %d of object %d points at %q", offsets[n], n, data[offsets[n]:min(offsets[n]+20, len(data))])
+				}
+				if string(m[1]) != strconv.Itoa(n) {
+					t.Errorf("offset of object %d points at object %s", n, m[1])
+				}
+			}
+
+			// Every object in the file is in the table, none is left out
+			if got := bytes.Count(data, []byte(" 0 obj\n")); got != len(offsets)-1 {
+				t.Errorf("file has %d objects, xref lists %d", got, len(offsets)-1)
+			}
+		})
+	}
+}
+
+func TestContentStreams(t *testing.T) {
+	data, err := sampleSheet(2).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lengths := regexp.MustCompile(`<< /Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+	matches := lengths.FindAllSubmatchIndex(data, -1)
+	if len(matches) != 2 {
+		t.Fatalf("found %d content streams, want 2", len(matches))
+	}
+
+	for i, m := range matches {
+		length, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		start := m[1]
+		if !bytes.HasPrefix(data[start+length:], []byte("\nendstream\n")) {
+			t.Fatalf("stream %d: /Length %d doesn't end at endstream", i, length)
+		}
+
+		zr, err := zlib.NewReader(bytes.NewReader(data[start : start+length]))
+		if err != nil {
+			t.Fatalf("stream %d: %v", i, err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("stream %d: %v", i, err)
+		}
+
+		for _, want := range []string{
+			"0.25 w 36 811.89 m 36 829.89 l S\n",
+			"60 700 13.5 4.5 re f\n",
+			fmt.Sprintf("BT /F2 21.6 Tf 80 600 Td (Mesa %d) Tj ET\n", i+1),
+			"BT /F1 12 Tf 80 580 Td (Caf\xe9 \\(Palermo\\) \\\\ A\xf1o 2024 ? 10\x80) Tj ET\n",
+		} {
+			if !bytes.Contains(content, []byte(want)) {
+				t.Errorf("stream %d is missing %q", i, want)
+			}
+		}
+	}
+}
+
+func TestNum(t *testing.T) {
+	tests := map[float64]string{
+		0:       "0",
+		-0.001:  "0",
+		36:      "36",
+		595.28:  "595.28",
+		4.5:     "4.5",
+		1.005e1: "10.05",
+		-12.3:   "-12.3",
+		0.126:   "0.13",
+	}
+	for in, want := range tests {
+		if got := num(in); got != want {
+			t.Errorf("num(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		font Font
+		text string
+		want int // In 1/1000 em, from the Adobe font metrics
+	}{
+		{Helvetica, "", 0},
+		{Helvetica, "Hello", 722 + 556 + 222 + 222 + 556},
+		{HelveticaBold, "Hello", 722 + 556 + 278 + 278 + 611},
+		{Helvetica, "Mesa 12", 833 + 556 + 500 + 556 + 278 + 556 + 556},
+		{HelveticaBold, "W@i", 944 + 975 + 278},
+		{Helvetica, "~ }", 584 + 278 + 334},
+		{Helvetica, "Año", 667 + 556 + 556},
+		{HelveticaBold, "¿Ñ?", 611 + 722 + 611},
+		{Helvetica, "¡°", 333 + 400},
+		{Helvetica, "日", defaultWidth},
+	}
+	for _, tt := range tests {
+		if got, want := TextWidth(tt.font, 10, tt.text), float64(tt.want)/100; got != want {
+			t.Errorf("TextWidth(%s, 10, %q) = %v, want %v", tt.font.baseFont(), tt.text, got, want)
+		}
+	}
+}
@@ -1,56 +1,133 @@
 package pkg
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"strconv"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // QRService handles QR code generation
 type QRService struct {
-	baseURL string
+	baseURL          string
+	shortLinkBaseURL string
+	secret           []byte
+	// legacyAcceptedUntil is when QR codes printed before table tokens stop working;
+	// zero means they have no end date
+	legacyAcceptedUntil time.Time
 }
 
-// NewQRService creates a new QR service
-func NewQRService(baseURL string) *QRService {
+// NewQRService creates a new QR service. secret signs the table tokens in QR URLs
+// and shortLinkBaseURL is the public URL of the API serving /q/:slug. Legacy QR
+// codes are accepted until legacyAcceptedUntil; a zero time accepts them with no end date.
+func NewQRService(baseURL, shortLinkBaseURL, secret string, legacyAcceptedUntil time.Time) *QRService {
 	return &QRService{
-		baseURL: baseURL,
+		baseURL:             baseURL,
+		shortLinkBaseURL:    shortLinkBaseURL,
+		secret:              []byte(secret),
+		legacyAcceptedUntil: legacyAcceptedUntil,
 	}
 }
 
-// GenerateTableQRCode generates a unique QR code for a table
-// The QR code contains a URL that points to the request page with encrypted data
-func (s *QRService) GenerateTableQRCode(restaurantID, branchID, tableID primitive.ObjectID, tableNumber int) string {
-	// Create a payload with restaurant, branch and table info
-	payload := fmt.Sprintf("%s:%s:%s:%d", restaurantID.Hex(), branchID.Hex(), tableID.Hex(), tableNumber)
+// GenerateTableToken returns a new random, URL-safe table token.
+// Tokens never change, so printed QR codes survive table renumbering.
+func (s *QRService) GenerateTableToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// slugAlphabet holds the base62 characters used in short link slugs
+const slugAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// slugLength is the number of characters in a short link slug
+const slugLength = 8
 
-	// Generate a hash for security (could be enhanced with encryption)
-	hash := sha256.Sum256([]byte(payload))
-	encodedPayload := base64.URLEncoding.EncodeToString(hash[:])
+// GenerateSlug returns a new random 8-character base62 short link slug
+func (s *QRService) GenerateSlug() string {
+	slug := make([]byte, 0, slugLength)
+	b := make([]byte, 1)
+	for len(slug) < slugLength {
+		if _, err := rand.Read(b); err != nil {
+			panic(fmt.Sprintf("crypto/rand failed: %v", err))
+		}
+		// Reject bytes past the last multiple of 62 so every character is equally likely
+		if int(b[0]) >= 256-256%len(slugAlphabet) {
+			continue
+		}
+		slug = append(slug, slugAlphabet[int(b[0])%len(slugAlphabet)])
+	}
+	return string(slug)
+}
 
-	// Create the QR code URL
-	// Format: https://tepidolacuenta.com/request?r=restaurantID&b=branchID&t=tableID&n=number&h=hash
-	qrCodeURL := fmt.Sprintf("%s/request?r=%s&b=%s&t=%s&n=%d&h=%s",
-		s.baseURL,
-		restaurantID.Hex(),
-		branchID.Hex(),
-		tableID.Hex(),
-		tableNumber,
-		encodedPayload[:16], // Use first 16 chars of hash
-	)
+// ShortLinkURL returns the short link URL for a slug.
+// Format: https://api.tepidolacuenta.com/q/slug
+func (s *QRService) ShortLinkURL(slug string) string {
+	return fmt.Sprintf("%s/q/%s", s.shortLinkBaseURL, slug)
+}
 
-	return qrCodeURL
+// GenerateTableQRCode generates the QR code URL for a table.
+// The URL only carries the table token, the QR version and their signature;
+// the restaurant, branch and table number are resolved server-side when the
+// code is scanned. Version 0 (never rotated) is left out of the URL.
+// Format: https://tepidolacuenta.com/request?k=token&v=version&h=signature
+func (s *QRService) GenerateTableQRCode(token string, version int) string {
+	if version == 0 {
+		return fmt.Sprintf("%s/request?k=%s&h=%s", s.baseURL, token, s.sign(token, version))
+	}
+	return fmt.Sprintf("%s/request?k=%s&v=%d&h=%s", s.baseURL, token, version, s.sign(token, version))
 }
 
-// ValidateTableQRCode validates that a QR code is authentic
-func (s *QRService) ValidateTableQRCode(restaurantID, branchID, tableID primitive.ObjectID, tableNumber int, hash string) bool {
+// ValidateTableQRCode validates that a token QR code is authentic and was issued
+// for the table's current QR version. Codes from a rotated-out version return
+// ErrQRCodeRevoked; anything else that doesn't check out returns ErrInvalidQRCode.
+func (s *QRService) ValidateTableQRCode(token string, version int, hash string, currentVersion int) error {
+	if !hmac.Equal([]byte(s.sign(token, version)), []byte(hash)) || version > currentVersion {
+		return ErrInvalidQRCode
+	}
+	if version < currentVersion {
+		return ErrQRCodeRevoked
+	}
+	return nil
+}
+
+// ValidateLegacyTableQRCode validates QR codes printed before table tokens,
+// whose URL carried the restaurant, branch and table IDs and the table number
+// at the time it was printed. Their hash isn't keyed, so anyone who knows the
+// IDs can forge one: once a cutoff is configured, they return ErrQRCodeRevoked
+// after it. Codes that don't check out return ErrInvalidQRCode.
+func (s *QRService) ValidateLegacyTableQRCode(restaurantID, branchID, tableID primitive.ObjectID, tableNumber int, hash string) error {
+	if !s.legacyAcceptedUntil.IsZero() && !time.Now().Before(s.legacyAcceptedUntil) {
+		return ErrQRCodeRevoked
+	}
+
 	// Generate expected hash
 	payload := fmt.Sprintf("%s:%s:%s:%d", restaurantID.Hex(), branchID.Hex(), tableID.Hex(), tableNumber)
 	expectedHash := sha256.Sum256([]byte(payload))
 	expectedEncoded := base64.URLEncoding.EncodeToString(expectedHash[:])
 
 	// Compare first 16 chars
-	return expectedEncoded[:16] == hash
+	if expectedEncoded[:16] != hash {
+		return ErrInvalidQRCode
+	}
+	return nil
+}
+
+// sign returns the first 16 chars of the HMAC-SHA256 of the token and QR version.
+// Version 0 signs the bare token, as codes were signed before rotation existed.
+func (s *QRService) sign(token string, version int) string {
+	payload := "table:" + token
+	if version > 0 {
+		payload += ":" + strconv.Itoa(version)
+	}
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))[:16]
 }
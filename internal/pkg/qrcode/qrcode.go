@@ -0,0 +1,566 @@
+// Package qrcode is a small QR Code (ISO/IEC 18004) encoder written in pure Go,
+// so table QR images can be rendered without cgo or external tools.
+// It only supports byte mode, which covers the table URLs we encode.
+package qrcode
+
+import (
+	"errors"
+	"strings"
+)
+
+// Level is the error correction level of a QR code
+type Level int
+
+// Error correction levels, from lowest to highest redundancy.
+// H recovers roughly 30% of the symbol, which leaves room for a centered logo.
+const (
+	LevelL Level = iota
+	LevelM
+	LevelQ
+	LevelH
+)
+
+// ErrDataTooLong is returned when the data doesn't fit in a version 40 symbol
+var ErrDataTooLong = errors.New("data too long for a QR code")
+
+// ParseLevel parses "L", "M", "Q" or "H" (case insensitive)
+func ParseLevel(s string) (Level, bool) {
+	switch strings.ToUpper(s) {
+	case "L":
+		return LevelL, true
+	case "M":
+		return LevelM, true
+	case "Q":
+		return LevelQ, true
+	case "H":
+		return LevelH, true
+	}
+	return 0, false
+}
+
+// formatBits is the 2-bit level indicator stored in the format information
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// eccCodewordsPerBlock and numEccBlocks are indexed by [level][version]; index 0 is unused.
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numEccBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// Code is an encoded QR symbol. Modules are indexed [y][x]; true is dark.
+type Code struct {
+	Version int
+	Level   Level
+	Size    int
+	Modules [][]bool
+
+	isFunction [][]bool
+}
+
+// Dark reports whether the module at (x, y) is dark. Out of range modules are light.
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.Modules[y][x]
+}
+
+// Encode encodes text in byte mode using the smallest version that fits at the given level
+func Encode(text string, level Level) (*Code, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if segmentBits(len(data), v) <= numDataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrDataTooLong
+	}
+
+	code := newCode(version, level)
+	code.drawFunctionPatterns()
+	code.drawCodewords(code.addEccAndInterleave(code.encodeData(data)))
+	code.applyBestMask()
+	code.isFunction = nil
+
+	return code, nil
+}
+
+// charCountBits is the width of the byte mode character count field
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// segmentBits is the length of a byte mode segment holding n bytes
+func segmentBits(n, version int) int {
+	if n >= 1<<charCountBits(version) {
+		return 1 << 30
+	}
+	return 4 + charCountBits(version) + n*8
+}
+
+// numRawDataModules is the number of modules left for data and ECC once
+// the function patterns are drawn
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int, level Level) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numEccBlocks[level][version]
+}
+
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	size := version*4 + 17
+
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, size-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+func newCode(version int, level Level) *Code {
+	size := version*4 + 17
+	code := &Code{
+		Version:    version,
+		Level:      level,
+		Size:       size,
+		Modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+	for i := range code.Modules {
+		code.Modules[i] = make([]bool, size)
+		code.isFunction[i] = make([]bool, size)
+	}
+	return code
+}
+
+// --- Function patterns ---
+
+func (c *Code) setFunctionModule(x, y int, dark bool) {
+	c.Modules[y][x] = dark
+	c.isFunction[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	// Timing patterns
+	for i := 0; i < c.Size; i++ {
+		c.setFunctionModule(6, i, i%2 == 0)
+		c.setFunctionModule(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their separators
+	c.drawFinderPattern(3, 3)
+	c.drawFinderPattern(c.Size-4, 3)
+	c.drawFinderPattern(3, c.Size-4)
+
+	// Alignment patterns, skipping the three corners taken by finders
+	positions := alignmentPatternPositions(c.Version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignmentPattern(x, y)
+		}
+	}
+
+	// Reserve the format areas with a dummy mask; the real one is drawn after masking
+	c.drawFormatBits(0)
+	c.drawVersion()
+}
+
+func (c *Code) drawFinderPattern(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunctionModule(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignmentPattern(cx, cy int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunctionModule(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the 15-bit format information (level + mask, BCH coded)
+func (c *Code) drawFormatBits(mask int) {
+	data := c.Level.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	// First copy, around the top-left finder
+	for i := 0; i <= 5; i++ {
+		c.setFunctionModule(8, i, bit(bits, i))
+	}
+	c.setFunctionModule(8, 7, bit(bits, 6))
+	c.setFunctionModule(8, 8, bit(bits, 7))
+	c.setFunctionModule(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunctionModule(14-i, 8, bit(bits, i))
+	}
+
+	// Second copy, split between the other two finders
+	for i := 0; i < 8; i++ {
+		c.setFunctionModule(c.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunctionModule(8, c.Size-15+i, bit(bits, i))
+	}
+	c.setFunctionModule(8, c.Size-8, true) // Always dark
+}
+
+// drawVersion draws the two copies of the 18-bit version information (versions 7 and up)
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+
+	rem := c.Version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := c.Version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		dark := bit(bits, i)
+		a, b := c.Size-11+i%3, i/3
+		c.setFunctionModule(a, b, dark)
+		c.setFunctionModule(b, a, dark)
+	}
+}
+
+// --- Data encoding ---
+
+// encodeData builds the data codewords: mode, count, payload, terminator and padding
+func (c *Code) encodeData(data []byte) []byte {
+	capacity := numDataCodewords(c.Version, c.Level) * 8
+
+	var bb bitBuffer
+	bb.append(0x4, 4) // Byte mode
+	bb.append(len(data), charCountBits(c.Version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+
+	bb.append(0, min(4, capacity-bb.len()))
+	bb.append(0, (8-bb.len()%8)%8)
+	for pad := 0xEC; bb.len() < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	return bb.bytes()
+}
+
+// addEccAndInterleave splits the data into blocks, appends the Reed-Solomon
+// codewords of each and interleaves them in the order they're placed in the symbol
+func (c *Code) addEccAndInterleave(data []byte) []byte {
+	numBlocks := numEccBlocks[c.Level][c.Version]
+	blockEccLen := eccCodewordsPerBlock[c.Level][c.Version]
+	rawCodewords := numRawDataModules(c.Version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockEccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		datLen := shortBlockLen - blockEccLen
+		if i >= numShortBlocks {
+			datLen++
+		}
+		dat := data[k : k+datLen]
+		k += datLen
+
+		block := make([]byte, 0, shortBlockLen+1)
+		block = append(block, dat...)
+		if i < numShortBlocks {
+			block = append(block, 0) // Placeholder so all blocks line up, skipped below
+		}
+		block = append(block, reedSolomonRemainder(dat, divisor)...)
+		blocks[i] = block
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockEccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// drawCodewords places the codewords in the zigzag order, two columns at a time
+// from the bottom-right corner, skipping function modules
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert // Upward column pair
+				}
+				if !c.isFunction[y][x] && i < len(data)*8 {
+					c.Modules[y][x] = bit(int(data[i>>3]), 7-i&7)
+					i++
+				}
+			}
+		}
+	}
+}
+
+// --- Masking ---
+
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// applyMask XORs the mask over the data modules. Applying it twice undoes it.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.isFunction[y][x] && maskBit(mask, x, y) {
+				c.Modules[y][x] = !c.Modules[y][x]
+			}
+		}
+	}
+}
+
+// applyBestMask tries the eight masks and keeps the one with the lowest penalty
+func (c *Code) applyBestMask() {
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask)
+	}
+
+	c.applyMask(best)
+	c.drawFormatBits(best)
+}
+
+// penalty scores the symbol with the four rules of the spec; lower is easier to scan
+func (c *Code) penalty() int {
+	const (
+		penaltyRun    = 3
+		penaltyBlock  = 3
+		penaltyFinder = 40
+		penaltyDark   = 10
+	)
+
+	n := c.Size
+	at := func(x, y int, horizontal bool) bool {
+		if horizontal {
+			return c.Modules[y][x]
+		}
+		return c.Modules[x][y]
+	}
+
+	result := 0
+	for _, horizontal := range []bool{true, false} {
+		for line := 0; line < n; line++ {
+			// Rule 1: runs of five or more same-colored modules
+			run := 1
+			for i := 1; i < n; i++ {
+				if at(i, line, horizontal) == at(i-1, line, horizontal) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					result += penaltyRun + run - 5
+				}
+				run = 1
+			}
+			if run >= 5 {
+				result += penaltyRun + run - 5
+			}
+
+			// Rule 3: finder-like 1:1:3:1:1 patterns with four light modules on one side
+			for i := 0; i+7 <= n; i++ {
+				if at(i, line, horizontal) && !at(i+1, line, horizontal) && at(i+2, line, horizontal) &&
+					at(i+3, line, horizontal) && at(i+4, line, horizontal) && !at(i+5, line, horizontal) &&
+					at(i+6, line, horizontal) &&
+					(c.isLightRun(line, i-4, i, horizontal) || c.isLightRun(line, i+7, i+11, horizontal)) {
+					result += penaltyFinder
+				}
+			}
+		}
+	}
+
+	// Rule 2: 2x2 blocks of the same color
+	dark := 0
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if c.Modules[y][x] {
+				dark++
+			}
+			if x+1 < n && y+1 < n {
+				m := c.Modules[y][x]
+				if m == c.Modules[y][x+1] && m == c.Modules[y+1][x] && m == c.Modules[y+1][x+1] {
+					result += penaltyBlock
+				}
+			}
+		}
+	}
+
+	// Rule 4: dark/light balance, per 5% away from 50%
+	total := n * n
+	result += abs(dark*20-total*10) / total * penaltyDark
+
+	return result
+}
+
+// isLightRun reports whether modules [from, to) of a row or column are light.
+// Modules outside the symbol count as light, like the quiet zone.
+func (c *Code) isLightRun(line, from, to int, horizontal bool) bool {
+	for i := max(from, 0); i < min(to, c.Size); i++ {
+		if horizontal && c.Modules[line][i] || !horizontal && c.Modules[i][line] {
+			return false
+		}
+	}
+	return true
+}
+
+// --- Reed-Solomon over GF(2^8) with the 0x11D polynomial ---
+
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// --- Helpers ---
+
+type bitBuffer struct {
+	bits []bool
+}
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		b.bits = append(b.bits, (value>>i)&1 != 0)
+	}
+}
+
+func (b *bitBuffer) len() int {
+	return len(b.bits)
+}
+
+func (b *bitBuffer) bytes() []byte {
+	result := make([]byte, (len(b.bits)+7)/8)
+	for i, set := range b.bits {
+		if set {
+			result[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+	return result
+}
+
+func bit(x, i int) bool {
+	return (x>>i)&1 != 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
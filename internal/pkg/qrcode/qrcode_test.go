@@ -0,0 +1,410 @@
+package qrcode
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var (
+	inputHello = "hello world"
+	inputURL   = "https://tepidolacuenta.com/request?k=q7Xk2mP9vR4tW1zY8bN3cA&h=GUyQvt7LbzYbdaX9"
+	inputWords = strings.Repeat("tepidolacuenta ", 12)
+	inputLong  = strings.Repeat("abcdefghijklmnopqrstuvwxyz", 20)
+)
+
+// knownAnswers were produced by an independent encoder (github.com/skip2/go-qrcode)
+// without a quiet zone. Each hash is the SHA-256 of the symbol drawn one row per
+// line with '#' for dark and '.' for light modules.
+var knownAnswers = []struct {
+	input   string
+	level   Level
+	version int
+	hash    string
+}{
+	{inputHello, LevelL, 1, "a7f44f037d4d2aa1ea0a27ec9a8152e26cd568ca9915b3fc57f14c1fb0cbafa4"},
+	{inputHello, LevelQ, 1, "af796baa82c62b3fafa57df65f2a7cf475f25c2062a46a2041e08cd090003c16"},
+	{inputHello, LevelH, 2, "8fd7e539e29a0220f3315fdf0353d4a23707112382d6eb9c454e2e11722bfa5a"},
+	{inputURL, LevelL, 4, "34a6e491b89cdf977e0f37cc5d374fc9db75b399b39282f69397b07672a975f7"},
+	{inputURL, LevelQ, 7, "28e38d8d74adc64ff1fe88fe632181b1bb5eecc5a1a92e333edb7106e430f29f"},
+	{inputURL, LevelH, 8, "c99af78d59fadd0686f82b5df07334bc2fdc2052f620081ab2de57d4f2756ba3"},
+	{inputWords, LevelL, 8, "4f9652542d7ed9330891776ccb6dd4b2015ef6286ecf6a093f8a1aaa85633a04"},
+	{inputWords, LevelM, 9, "205748b396b85a5f79b003bcfc7b50beddd1a6d45ab7ca6386979b721d030280"},
+	{inputWords, LevelQ, 12, "455e3c6aa21e3011c6377b1c6e5b99a9f1a16232f2ef6facd4029c99e3d54693"},
+	{inputWords, LevelH, 14, "ffd941beafc01297745f1a22a27a30b4262d060313e5f49259eac6209cd33967"},
+	{inputLong, LevelL, 15, "247f37944e781646e45c750770461208288f8abd6a220577787c8ba080d7dcd4"},
+	{inputLong, LevelM, 18, "19da8187f52b108b638ac3388eed877102507b0456c168fec1575f8d6c16673b"},
+	{inputLong, LevelH, 25, "873367603a4f5e5684beb110f9a445e8fd43684447cf404996a237437e766e99"},
+}
+
+func TestEncodeKnownAnswers(t *testing.T) {
+	for _, tc := range knownAnswers {
+		code, err := Encode(tc.input, tc.level)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes, level %d): %v", len(tc.input), tc.level, err)
+		}
+		if code.Version != tc.version {
+			t.Errorf("Encode(%d bytes, level %d): version %d, want %d", len(tc.input), tc.level, code.Version, tc.version)
+			continue
+		}
+		if got := symbolHash(code); got != tc.hash {
+			t.Errorf("Encode(%d bytes, level %d): symbol hash %s, want %s", len(tc.input), tc.level, got, tc.hash)
+		}
+	}
+}
+
+// The reference encoder scores masks slightly differently, so in these cases it
+// picks another mask. With that mask forced, the symbols must be identical.
+func TestEncodeWithReferenceMask(t *testing.T) {
+	cases := []struct {
+		input   string
+		level   Level
+		version int
+		mask    int
+		hash    string
+	}{
+		{inputHello, LevelM, 1, 2, "4a57066fd797f63b243b89cfffee6f56cf6b404e7e547c5959c17d0903a116a0"},
+		{inputURL, LevelM, 5, 4, "cbffa67661ca5b04033a0edaf0455826ba479fde0d943c9f92cfcd8e07dc30d8"},
+		{inputLong, LevelQ, 22, 5, "2c8a66a32f2410970d0750c8c981299184a57bcde2ad1988ae86821cdef7191e"},
+	}
+
+	for _, tc := range cases {
+		code := newCode(tc.version, tc.level)
+		code.drawFunctionPatterns()
+		code.drawCodewords(code.addEccAndInterleave(code.encodeData([]byte(tc.input))))
+		code.applyMask(tc.mask)
+		code.drawFormatBits(tc.mask)
+
+		if got := symbolHash(code); got != tc.hash {
+			t.Errorf("version %d level %d mask %d: symbol hash %s, want %s", tc.version, tc.level, tc.mask, got, tc.hash)
+		}
+	}
+}
+
+func TestEncodeMatrix(t *testing.T) {
+	want := strings.Join([]string{
+		"#######..#.##.#######",
+		"#.....#.##.#..#.....#",
+		"#.###.#.##..#.#.###.#",
+		"#.###.#..#.#..#.###.#",
+		"#.###.#.#...#.#.###.#",
+		"#.....#.#..##.#.....#",
+		"#######.#.#.#.#######",
+		"........#####........",
+		"##.#..##.##...###.##.",
+		"...#.#.####...###..##",
+		"#.#..##..##.#..#.##.#",
+		".##.##.#####..#.##.##",
+		"###.#.##..#.##.##....",
+		"........#.##......#.#",
+		"#######.#.....######.",
+		"#.....#..####..#....#",
+		"#.###.#....#.#.....#.",
+		"#.###.#.###....######",
+		"#.###.#..#..#.#.#.#.#",
+		"#.....#.#..#.#.......",
+		"#######.##..#.##.#.#.",
+	}, "\n") + "\n"
+
+	code, err := Encode(inputHello, LevelL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := drawSymbol(code); got != want {
+		t.Errorf("Encode(%q, L) =\n%s\nwant\n%s", inputHello, got, want)
+	}
+}
+
+// The Reed-Solomon example of ISO/IEC 18004 Annex I: "01234567" as a 1-M symbol
+func TestReedSolomonRemainder(t *testing.T) {
+	data := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	want := []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}
+
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Errorf("reedSolomonRemainder = % X, want % X", got, want)
+	}
+}
+
+// Format information after masking with 0x5412, from ISO/IEC 18004 Annex C
+func TestFormatBits(t *testing.T) {
+	want := map[Level][8]int{
+		LevelL: {0x77C4, 0x72F3, 0x7DAA, 0x789D, 0x662F, 0x6318, 0x6C41, 0x6976},
+		LevelM: {0x5412, 0x5125, 0x5E7C, 0x5B4B, 0x45F9, 0x40CE, 0x4F97, 0x4AA0},
+		LevelQ: {0x355F, 0x3068, 0x3F31, 0x3A06, 0x24B4, 0x2183, 0x2EDA, 0x2BED},
+		LevelH: {0x1689, 0x13BE, 0x1CE7, 0x19D0, 0x0762, 0x0255, 0x0D0C, 0x083B},
+	}
+
+	for level, masks := range want {
+		for mask, bits := range masks {
+			code := newCode(1, level)
+			code.drawFormatBits(mask)
+			first, second := readFormatBits(code)
+			if first != bits || second != bits {
+				t.Errorf("level %d mask %d: format bits %#04x and %#04x, want %#04x", level, mask, first, second, bits)
+			}
+		}
+	}
+}
+
+// Version information from ISO/IEC 18004 Annex D
+func TestVersionBits(t *testing.T) {
+	want := map[int]int{
+		7: 0x07C94, 8: 0x085BC, 9: 0x09A99, 10: 0x0A4D3, 11: 0x0BBF6, 12: 0x0C762, 13: 0x0D847,
+		14: 0x0E60D, 15: 0x0F928, 16: 0x10B78, 17: 0x1145D, 18: 0x12A17, 19: 0x13532, 20: 0x149A6,
+		21: 0x15683, 22: 0x168C9, 23: 0x177EC, 24: 0x18EC4, 25: 0x191E1, 26: 0x1AFAB, 27: 0x1B08E,
+		28: 0x1CC1A, 29: 0x1D33F, 30: 0x1ED75, 31: 0x1F250, 32: 0x209D5, 33: 0x216F0, 34: 0x228BA,
+		35: 0x2379F, 36: 0x24B0B, 37: 0x2542E, 38: 0x26A64, 39: 0x27541, 40: 0x28C69,
+	}
+
+	for version, bits := range want {
+		code := newCode(version, LevelL)
+		code.drawVersion()
+
+		var topRight, bottomLeft int
+		for i := 0; i < 18; i++ {
+			a, b := code.Size-11+i%3, i/3
+			if code.Modules[b][a] {
+				topRight |= 1 << i
+			}
+			if code.Modules[a][b] {
+				bottomLeft |= 1 << i
+			}
+		}
+		if topRight != bits || bottomLeft != bits {
+			t.Errorf("version %d: version bits %#05x and %#05x, want %#05x", version, topRight, bottomLeft, bits)
+		}
+	}
+}
+
+// Decodes symbols of every version and level back to their data, checking the
+// Reed-Solomon codewords of each block on the way
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, level := range []Level{LevelL, LevelM, LevelQ, LevelH} {
+		for version := 1; version <= 40; version++ {
+			// The longest input that still fits in this version
+			n := (numDataCodewords(version, level)*8 - 4 - charCountBits(version)) / 8
+			if n >= 1<<charCountBits(version) {
+				n = 1<<charCountBits(version) - 1
+			}
+			input := make([]byte, n)
+			for i := range input {
+				input[i] = byte(i*7 + version)
+			}
+
+			code, err := Encode(string(input), level)
+			if err != nil {
+				t.Fatalf("version %d level %d: %v", version, level, err)
+			}
+			if code.Version != version {
+				t.Errorf("%d bytes at level %d: version %d, want %d", n, level, code.Version, version)
+				continue
+			}
+
+			got, err := decode(code)
+			if err != nil {
+				t.Errorf("version %d level %d: %v", version, level, err)
+				continue
+			}
+			if !bytes.Equal(got, input) {
+				t.Errorf("version %d level %d: decoded data doesn't match the input", version, level)
+			}
+		}
+	}
+}
+
+// Byte mode capacity of each version, from ISO/IEC 18004 Table 7. It checks the
+// error correction tables independently of the round trip above.
+func TestByteCapacity(t *testing.T) {
+	capacity := map[Level][40]int{
+		LevelL: {17, 32, 53, 78, 106, 134, 154, 192, 230, 271, 321, 367, 425, 458, 520, 586, 644, 718, 792, 858, 929, 1003, 1091, 1171, 1273, 1367, 1465, 1528, 1628, 1732, 1840, 1952, 2068, 2188, 2303, 2431, 2563, 2699, 2809, 2953},
+		LevelM: {14, 26, 42, 62, 84, 106, 122, 152, 180, 213, 251, 287, 331, 362, 412, 450, 504, 560, 624, 666, 711, 779, 857, 911, 997, 1059, 1125, 1190, 1264, 1370, 1452, 1538, 1628, 1722, 1809, 1911, 1989, 2099, 2213, 2331},
+		LevelQ: {11, 20, 32, 46, 60, 74, 86, 108, 130, 151, 177, 203, 241, 258, 292, 322, 364, 394, 442, 482, 509, 565, 611, 661, 715, 751, 805, 868, 908, 982, 1030, 1112, 1168, 1228, 1283, 1351, 1423, 1499, 1579, 1663},
+		LevelH: {7, 14, 24, 34, 44, 58, 64, 84, 98, 119, 137, 155, 177, 194, 220, 250, 280, 310, 338, 382, 403, 439, 461, 511, 535, 593, 625, 658, 698, 742, 790, 842, 898, 958, 983, 1051, 1093, 1139, 1219, 1273},
+	}
+
+	for level, capacities := range capacity {
+		for i, n := range capacities {
+			version := i + 1
+			if got := numDataCodewords(version, level)*8 - 4 - charCountBits(version); got/8 != n {
+				t.Errorf("version %d level %d holds %d bytes, want %d", version, level, got/8, n)
+			}
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	// A version 40-L symbol holds 2953 bytes
+	if _, err := Encode(strings.Repeat("a", 2953), LevelL); err != nil {
+		t.Errorf("Encode(2953 bytes, L): %v", err)
+	}
+	if _, err := Encode(strings.Repeat("a", 2954), LevelL); !errors.Is(err, ErrDataTooLong) {
+		t.Errorf("Encode(2954 bytes, L): err = %v, want ErrDataTooLong", err)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, want := range map[string]Level{"L": LevelL, "m": LevelM, "Q": LevelQ, "h": LevelH} {
+		if got, ok := ParseLevel(s); !ok || got != want {
+			t.Errorf("ParseLevel(%q) = %d, %v, want %d", s, got, ok, want)
+		}
+	}
+	if _, ok := ParseLevel("X"); ok {
+		t.Error(`ParseLevel("X") succeeded`)
+	}
+}
+
+// drawSymbol draws the symbol one row per line, '#' for dark and '.' for light
+func drawSymbol(code *Code) string {
+	var b strings.Builder
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Dark(x, y) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func symbolHash(code *Code) string {
+	sum := sha256.Sum256([]byte(drawSymbol(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// readFormatBits reads the two copies of the format information, bit 0 first
+func readFormatBits(code *Code) (int, int) {
+	var positions [15][2]int
+	for i := 0; i <= 5; i++ {
+		positions[i] = [2]int{8, i}
+	}
+	positions[6] = [2]int{8, 7}
+	positions[7] = [2]int{8, 8}
+	positions[8] = [2]int{7, 8}
+	for i := 9; i < 15; i++ {
+		positions[i] = [2]int{14 - i, 8}
+	}
+
+	first, second := 0, 0
+	for i, p := range positions {
+		if code.Dark(p[0], p[1]) {
+			first |= 1 << i
+		}
+	}
+	for i := 0; i < 15; i++ {
+		x, y := code.Size-1-i, 8
+		if i >= 8 {
+			x, y = 8, code.Size-15+i
+		}
+		if code.Dark(x, y) {
+			second |= 1 << i
+		}
+	}
+	return first, second
+}
+
+// decode reads a symbol back: it finds the mask in the format information,
+// unmasks and collects the codewords, de-interleaves the blocks, verifies their
+// error correction codewords and parses the byte mode segment
+func decode(code *Code) ([]byte, error) {
+	format, _ := readFormatBits(code)
+	format ^= 0x5412
+	if format>>13 != code.Level.formatBits() {
+		return nil, errors.New("format information has the wrong level")
+	}
+	mask := format >> 10 & 7
+
+	layout := newCode(code.Version, code.Level)
+	layout.drawFunctionPatterns()
+
+	var bits []bool
+	for right := code.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < code.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = code.Size - 1 - vert
+				}
+				if !layout.isFunction[y][x] {
+					bits = append(bits, code.Modules[y][x] != maskBit(mask, x, y))
+				}
+			}
+		}
+	}
+
+	raw := make([]byte, numRawDataModules(code.Version)/8)
+	for i := range raw {
+		for j := 0; j < 8; j++ {
+			if bits[i*8+j] {
+				raw[i] |= 1 << (7 - j)
+			}
+		}
+	}
+
+	numBlocks := numEccBlocks[code.Level][code.Version]
+	eccLen := eccCodewordsPerBlock[code.Level][code.Version]
+	numShortBlocks := numBlocks - len(raw)%numBlocks
+	shortDataLen := len(raw)/numBlocks - eccLen
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortDataLen; i++ {
+		for j := range blocks {
+			if i < shortDataLen || j >= numShortBlocks {
+				blocks[j] = append(blocks[j], raw[k])
+				k++
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+
+	divisor := reedSolomonDivisor(eccLen)
+	var data []byte
+	for j, block := range blocks {
+		dataLen := len(block) - eccLen
+		if !bytes.Equal(reedSolomonRemainder(block[:dataLen], divisor), block[dataLen:]) {
+			return nil, fmt.Errorf("block %d has bad error correction codewords", j)
+		}
+		data = append(data, block[:dataLen]...)
+	}
+
+	var reader bitBuffer
+	for _, b := range data {
+		reader.append(int(b), 8)
+	}
+	read := func(from, length int) int {
+		v := 0
+		for i := from; i < from+length; i++ {
+			v <<= 1
+			if reader.bits[i] {
+				v |= 1
+			}
+		}
+		return v
+	}
+
+	if read(0, 4) != 0x4 {
+		return nil, errors.New("segment is not in byte mode")
+	}
+	countBits := charCountBits(code.Version)
+	n := read(4, countBits)
+	result := make([]byte, n)
+	for i := range result {
+		result[i] = byte(read(4+countBits+i*8, 8))
+	}
+	return result, nil
+}
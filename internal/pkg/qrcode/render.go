@@ -0,0 +1,181 @@
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // Logos can be JPEG
+	"image/png"
+	"os"
+	"strings"
+)
+
+// logoFraction is the share of the symbol width taken by a centered logo.
+// At level H this stays well inside what error correction can recover.
+const logoFraction = 0.22
+
+// Options controls how a code is rendered
+type Options struct {
+	// Size is the maximum width in pixels. Modules are drawn with a whole number
+	// of pixels each, so the image may be slightly smaller than requested.
+	Size int
+	// QuietZone is the light margin around the symbol, in modules (the spec asks for 4)
+	QuietZone int
+	// Logo is drawn centered over the symbol on a light box when set.
+	// Encode with LevelH when using one.
+	Logo image.Image
+}
+
+// LoadLogo reads a PNG or JPEG logo from disk
+func LoadLogo(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	logo, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode logo %s: %w", path, err)
+	}
+	return logo, nil
+}
+
+// logoBox returns the area, in modules, covered by a centered logo including its light padding
+func (c *Code) logoBox(opts Options) (x, y, size int) {
+	size = int(float64(c.Size) * logoFraction)
+	size += (c.Size - size) % 2 // Keep it centered on the module grid
+	offset := opts.QuietZone + (c.Size-size)/2
+	return offset, offset, size
+}
+
+// PNG renders the code as a PNG image
+func (c *Code) PNG(opts Options) ([]byte, error) {
+	total := c.Size + 2*opts.QuietZone
+	scale := max(opts.Size/total, 1)
+	width := total * scale
+
+	var img draw.Image
+	if opts.Logo != nil {
+		rgba := image.NewNRGBA(image.Rect(0, 0, width, width))
+		draw.Draw(rgba, rgba.Bounds(), image.White, image.Point{}, draw.Src)
+		img = rgba
+	} else {
+		// Two-color palette keeps plain codes tiny
+		img = image.NewPaletted(image.Rect(0, 0, width, width), color.Palette{color.White, color.Black})
+	}
+
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Modules[y][x] {
+				continue
+			}
+			px, py := (x+opts.QuietZone)*scale, (y+opts.QuietZone)*scale
+			draw.Draw(img, image.Rect(px, py, px+scale, py+scale), image.Black, image.Point{}, draw.Src)
+		}
+	}
+
+	if opts.Logo != nil {
+		bx, by, bsize := c.logoBox(opts)
+		box := image.Rect(bx*scale, by*scale, (bx+bsize)*scale, (by+bsize)*scale)
+		draw.Draw(img, box, image.White, image.Point{}, draw.Src)
+
+		// Leave one module of padding between the logo and the surrounding modules
+		inner := box.Inset(scale)
+		logo := fitImage(opts.Logo, inner.Dx(), inner.Dy())
+		offset := image.Pt((inner.Dx()-logo.Bounds().Dx())/2, (inner.Dy()-logo.Bounds().Dy())/2)
+		draw.Draw(img, logo.Bounds().Add(inner.Min.Add(offset)), logo, image.Point{}, draw.Over)
+	}
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the code as an SVG document. Dark modules are merged into
+// horizontal runs so the path stays compact.
+func (c *Code) SVG(opts Options) ([]byte, error) {
+	total := c.Size + 2*opts.QuietZone
+
+	var path strings.Builder
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; {
+			if !c.Modules[y][x] {
+				x++
+				continue
+			}
+			run := 1
+			for x+run < c.Size && c.Modules[y][x+run] {
+				run++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", x+opts.QuietZone, y+opts.QuietZone, run, run)
+			x += run
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" shape-rendering="crispEdges">`, total, total, opts.Size, opts.Size)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/>`, total, total)
+	fmt.Fprintf(&buf, `<path d="%s" fill="#000"/>`, path.String())
+
+	if opts.Logo != nil {
+		var logo bytes.Buffer
+		if err := png.Encode(&logo, opts.Logo); err != nil {
+			return nil, err
+		}
+
+		bx, by, bsize := c.logoBox(opts)
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="#fff"/>`, bx, by, bsize, bsize)
+		fmt.Fprintf(&buf, `<image x="%d" y="%d" width="%d" height="%d" preserveAspectRatio="xMidYMid meet" href="data:image/png;base64,%s"/>`,
+			bx+1, by+1, bsize-2, bsize-2, base64.StdEncoding.EncodeToString(logo.Bytes()))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+// fitImage scales src to fit in w x h keeping its aspect ratio.
+// Each destination pixel averages the source pixels it covers, which keeps
+// downscaled logos smooth without pulling in an imaging library.
+func fitImage(src image.Image, w, h int) *image.NRGBA {
+	sb := src.Bounds()
+	if sb.Dx() == 0 || sb.Dy() == 0 || w <= 0 || h <= 0 {
+		return image.NewNRGBA(image.Rect(0, 0, 0, 0))
+	}
+
+	if sb.Dx()*h > sb.Dy()*w {
+		h = max(sb.Dy()*w/sb.Dx(), 1)
+	} else {
+		w = max(sb.Dx()*h/sb.Dy(), 1)
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for dy := 0; dy < h; dy++ {
+		y0 := sb.Min.Y + dy*sb.Dy()/h
+		y1 := max(sb.Min.Y+(dy+1)*sb.Dy()/h, y0+1)
+		for dx := 0; dx < w; dx++ {
+			x0 := sb.Min.X + dx*sb.Dx()/w
+			x1 := max(sb.Min.X+(dx+1)*sb.Dx()/w, x0+1)
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := src.At(x, y).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			// The averages are premultiplied; Set converts them to NRGBA
+			c := color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)}
+			dst.Set(dx, dy, c)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"sync"
+	"time"
+)
+
+// SlidingWindowLimiter allows at most limit hits per key within a rolling window.
+// State is kept in memory, so limits are per API instance.
+// A nil limiter allows everything.
+type SlidingWindowLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	hits   map[string][]time.Time
+}
+
+// NewSlidingWindowLimiter creates a limiter. A limit of 0 or less disables it.
+func NewSlidingWindowLimiter(limit int, window time.Duration) *SlidingWindowLimiter {
+	if limit <= 0 || window <= 0 {
+		return nil
+	}
+	return &SlidingWindowLimiter{
+		limit:  limit,
+		window: window,
+		hits:   make(map[string][]time.Time),
+	}
+}
+
+// Allow records a hit for key and reports whether it is within the limit.
+// Rejected hits are not recorded, so a blocked client recovers once older hits expire.
+func (l *SlidingWindowLimiter) Allow(key string) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	recent := l.recentHits(key, now)
+	if len(recent) >= l.limit {
+		l.hits[key] = recent
+		return false
+	}
+
+	l.hits[key] = append(recent, now)
+	return true
+}
+
+// Prune drops keys with no hits inside the window. Run it periodically to keep memory bounded.
+func (l *SlidingWindowLimiter) Prune() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for key := range l.hits {
+		if recent := l.recentHits(key, now); len(recent) == 0 {
+			delete(l.hits, key)
+		} else {
+			l.hits[key] = recent
+		}
+	}
+}
+
+// recentHits returns the hits of key still inside the window. Callers must hold mu.
+func (l *SlidingWindowLimiter) recentHits(key string, now time.Time) []time.Time {
+	hits := l.hits[key]
+	cutoff := now.Add(-l.window)
+
+	i := 0
+	for i < len(hits) && !hits[i].After(cutoff) {
+		i++
+	}
+	return hits[i:]
+}
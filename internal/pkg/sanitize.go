@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NoteSanitizer cleans the free-text notes diners attach to their requests:
+// strips control/invisible characters, collapses whitespace, enforces a max
+// length and masks words from a configurable banned list.
+type NoteSanitizer struct {
+	maxLength   int
+	bannedWords map[string]bool
+}
+
+// NewNoteSanitizer creates a new note sanitizer. Banned words are matched
+// case-insensitively against whole words.
+func NewNoteSanitizer(maxLength int, bannedWords []string) *NoteSanitizer {
+	banned := make(map[string]bool, len(bannedWords))
+	for _, w := range bannedWords {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if w != "" {
+			banned[w] = true
+		}
+	}
+	return &NoteSanitizer{
+		maxLength:   maxLength,
+		bannedWords: banned,
+	}
+}
+
+// Sanitize returns the cleaned version of note. An empty result means the note
+// had no printable content.
+func (s *NoteSanitizer) Sanitize(note string) string {
+	// Control and format characters (zero-width, bidi overrides, etc.) become spaces
+	// so they can't be used to hide content or break the dashboard layout.
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) || r == unicode.ReplacementChar {
+			return ' '
+		}
+		return r
+	}, note)
+
+	words := strings.Fields(cleaned)
+	for i, w := range words {
+		words[i] = s.maskWord(w)
+	}
+	result := strings.Join(words, " ")
+
+	if s.maxLength > 0 {
+		runes := []rune(result)
+		if len(runes) > s.maxLength {
+			result = strings.TrimSpace(string(runes[:s.maxLength]))
+		}
+	}
+
+	return result
+}
+
+// maskWord replaces a banned word with asterisks, keeping surrounding punctuation
+func (s *NoteSanitizer) maskWord(word string) string {
+	if len(s.bannedWords) == 0 {
+		return word
+	}
+
+	isWordRune := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsNumber(r) }
+	start := strings.IndexFunc(word, isWordRune)
+	end := strings.LastIndexFunc(word, isWordRune)
+	if start < 0 {
+		return word
+	}
+	// LastIndexFunc returns the byte offset of the last rune's first byte
+	_, size := utf8.DecodeRuneInString(word[end:])
+	end += size
+
+	core := word[start:end]
+	if !s.bannedWords[strings.ToLower(core)] {
+		return word
+	}
+
+	return word[:start] + strings.Repeat("*", len([]rune(core))) + word[end:]
+}
@@ -0,0 +1,26 @@
+package pkg
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunEvery runs fn every interval until ctx is cancelled. Errors are logged and
+// the job keeps running, so a transient failure only delays the next tick.
+// It blocks, so call it in its own goroutine.
+func RunEvery(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Printf("Job %s failed: %v", name, err)
+			}
+		}
+	}
+}
@@ -14,8 +14,24 @@ type Client struct {
 	ID           string
 	UserID       string
 	RestaurantID primitive.ObjectID
-	Conn         *websocket.Conn
-	Send         chan []byte
+	// ZoneID, when set, limits the client to events of tables in that zone
+	// (e.g. a terrace waiter). Restaurant-wide events are always delivered.
+	ZoneID *primitive.ObjectID
+	Conn   *websocket.Conn
+	Send   chan []byte
+}
+
+// accepts reports whether the client should receive a broadcast message
+func (c *Client) accepts(message *BroadcastMessage) bool {
+	if !message.ZoneScoped || c.ZoneID == nil {
+		return true
+	}
+	for _, zoneID := range message.ZoneIDs {
+		if zoneID == *c.ZoneID {
+			return true
+		}
+	}
+	return false
 }
 
 // Hub maintains active WebSocket connections and broadcasts messages
@@ -38,7 +54,11 @@ type Hub struct {
 // BroadcastMessage represents a message to broadcast to a restaurant
 type BroadcastMessage struct {
 	RestaurantID primitive.ObjectID
-	Data         interface{}
+	// ZoneScoped messages only reach clients without a zone filter and
+	// clients subscribed to one of ZoneIDs
+	ZoneScoped bool
+	ZoneIDs    []primitive.ObjectID
+	Data       interface{}
 }
 
 // NewHub creates a new WebSocket hub
@@ -92,6 +112,9 @@ func (h *Hub) Run() {
 			}
 
 			for client := range clients {
+				if !client.accepts(message) {
+					continue
+				}
 				select {
 				case client.Send <- jsonData:
 				default:
@@ -125,6 +148,18 @@ func (h *Hub) Broadcast(restaurantID primitive.ObjectID, data interface{}) {
 	}
 }
 
+// BroadcastToZones sends a message about tables in the given zones to the clients
+// of a restaurant that follow all zones or one of them. With no zones (tables
+// without a zone) only clients without a zone filter receive it.
+func (h *Hub) BroadcastToZones(restaurantID primitive.ObjectID, zoneIDs []primitive.ObjectID, data interface{}) {
+	h.broadcast <- &BroadcastMessage{
+		RestaurantID: restaurantID,
+		ZoneScoped:   true,
+		ZoneIDs:      zoneIDs,
+		Data:         data,
+	}
+}
+
 // GetClientCount returns the number of connected clients for a restaurant
 func (h *Hub) GetClientCount(restaurantID primitive.ObjectID) int {
 	h.mu.RLock()
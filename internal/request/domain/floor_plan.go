@@ -0,0 +1,23 @@
+package domain
+
+import (
+	sessionDomain "juansecalvinio/tepidolacuenta/internal/session/domain"
+	tableDomain "juansecalvinio/tepidolacuenta/internal/table/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FloorPlan is a branch's floor plan together with the live request state of its tables
+type FloorPlan struct {
+	BranchID primitive.ObjectID `json:"branchId"`
+	Tables   []*FloorPlanTable  `json:"tables"`
+}
+
+// FloorPlanTable is a table of the floor plan with its open session, if any, and
+// its pending requests, at most one per type and the most urgent first. Tables
+// without a layout haven't been placed on the plan yet.
+type FloorPlanTable struct {
+	*tableDomain.Table
+	Session         *sessionDomain.TableSession `json:"session"`
+	PendingRequests []*Request                  `json:"pendingRequests"`
+}
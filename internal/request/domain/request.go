@@ -19,11 +19,27 @@ const (
 type PaymentMethod string
 
 const (
-	PaymentCash       PaymentMethod = "cash"
-	PaymentDebitCard  PaymentMethod = "debit_card"
-	PaymentCreditCard PaymentMethod = "credit_card"
+	PaymentCash          PaymentMethod = "cash"
+	PaymentDebitCard     PaymentMethod = "debit_card"
+	PaymentCreditCard    PaymentMethod = "credit_card"
+	PaymentMercadoPagoQR PaymentMethod = "mercado_pago_qr"
+	PaymentBankTransfer  PaymentMethod = "bank_transfer"
 )
 
+// RequestType is what the diner is asking for. Which types and payment methods
+// a branch accepts is configured in its settings.
+type RequestType string
+
+const (
+	// TypeBill asks for the bill and carries the payment method
+	TypeBill RequestType = "bill"
+	// TypeWaiter calls a waiter to the table
+	TypeWaiter RequestType = "waiter"
+)
+
+// MaxNoteLength is the maximum number of characters allowed in a diner note
+const MaxNoteLength = 280
+
 // Request represents an account request from a table
 type Request struct {
 	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
@@ -31,20 +47,44 @@ type Request struct {
 	BranchID      primitive.ObjectID `bson:"branchId" json:"branchId"`
 	TableID       primitive.ObjectID `bson:"tableId" json:"tableId"`
 	TableNumber   int                `bson:"tableNumber" json:"tableNumber"`
-	PaymentMethod PaymentMethod      `bson:"paymentMethod" json:"paymentMethod"`
-	Status        RequestStatus      `bson:"status" json:"status"`
-	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+	TableLabel    string             `bson:"tableLabel" json:"tableLabel,omitempty"`
+	TableCapacity int                `bson:"tableCapacity" json:"tableCapacity,omitempty"`
+	// ZoneID is the zone of the table when the request was made or last moved
+	ZoneID *primitive.ObjectID `bson:"zoneId,omitempty" json:"zoneId,omitempty"`
+	// SessionID is the table session the request was made in, if staff had seated the party
+	SessionID *primitive.ObjectID `bson:"sessionId,omitempty" json:"sessionId,omitempty"`
+	Type      RequestType         `bson:"type" json:"type"`
+	// PaymentMethod is only set on bill requests
+	PaymentMethod PaymentMethod `bson:"paymentMethod" json:"paymentMethod,omitempty"`
+	Note          string        `bson:"note,omitempty" json:"note,omitempty"`
+	Priority      bool          `bson:"priority" json:"priority"`
+	Status        RequestStatus `bson:"status" json:"status"`
+	CreatedAt     time.Time     `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time     `bson:"updatedAt" json:"updatedAt"`
+}
+
+// TableRef is the snapshot of a table's details copied onto its requests, so
+// listings and broadcasts can show them without looking the table up
+type TableRef struct {
+	ID       primitive.ObjectID
+	Number   int
+	Label    string
+	Capacity int
+	ZoneID   *primitive.ObjectID
+	Priority bool
 }
 
 // CreateRequestInput represents the input for creating a request
 type CreateRequestInput struct {
-	RestaurantID  string `json:"restaurantId" binding:"required"`
-	BranchID      string `json:"branchId" binding:"required"`
-	TableID       string `json:"tableId" binding:"required"`
-	TableNumber   int    `json:"tableNumber" binding:"required,min=1"`
-	Hash          string `json:"hash" binding:"required"`
-	PaymentMethod string `json:"paymentMethod" binding:"required,oneof=cash debit_card credit_card"`
+	TableQRParams
+	// Type defaults to bill; PaymentMethod is required for bill requests
+	Type          string `json:"type,omitempty" binding:"omitempty,oneof=bill waiter"`
+	PaymentMethod string `json:"paymentMethod,omitempty" binding:"omitempty,oneof=cash debit_card credit_card mercado_pago_qr bank_transfer"`
+	// Note is cut to MaxNoteLength by the note sanitizer
+	Note string `json:"note,omitempty"`
+	// Latitude and Longitude are the diner's browser geolocation, checked when the branch has a geofence
+	Latitude  *float64 `json:"latitude,omitempty" binding:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude,omitempty" binding:"required_with=Latitude,omitempty,min=-180,max=180"`
 }
 
 // UpdateRequestStatusInput represents the input for updating request status
@@ -52,13 +92,127 @@ type UpdateRequestStatusInput struct {
 	Status string `json:"status" binding:"required,oneof=pending attended cancelled"`
 }
 
+// TransferRequestInput represents the input for moving a request to another table
+type TransferRequestInput struct {
+	TableID string `json:"tableId" binding:"required"`
+}
+
+// MergeTablesInput represents the input for joining tables for a seating
+type MergeTablesInput struct {
+	TargetTableID  string   `json:"targetTableId" binding:"required"`
+	SourceTableIDs []string `json:"sourceTableIds" binding:"required,min=1,max=20,dive,required"`
+}
+
+// MergeTablesResult reports what happened to the pending requests of the merged tables
+type MergeTablesResult struct {
+	TargetTableID  primitive.ObjectID   `json:"targetTableId"`
+	SourceTableIDs []primitive.ObjectID `json:"sourceTableIds"`
+	Transferred    []*Request           `json:"transferred"`
+	Cancelled      []*Request           `json:"cancelled"`
+}
+
+// Hub event types for request table changes
+const (
+	EventRequestTransferred = "request.transferred"
+	EventTablesMerged       = "tables.merged"
+	EventTablesUnmerged     = "tables.unmerged"
+	EventTableFlagged       = "table.flagged"
+)
+
+// RequestTransferredEvent is the message sent over WebSocket when a request moves to another table
+type RequestTransferredEvent struct {
+	Type            string             `json:"type"`
+	Request         *Request           `json:"request"`
+	FromTableID     primitive.ObjectID `json:"fromTableId"`
+	FromTableNumber int                `json:"fromTableNumber"`
+	FromTableLabel  string             `json:"fromTableLabel,omitempty"`
+}
+
+// TablesMergeEvent is the message sent over WebSocket when tables are merged or unmerged
+type TablesMergeEvent struct {
+	Type           string               `json:"type"`
+	BranchID       primitive.ObjectID   `json:"branchId"`
+	TargetTableID  primitive.ObjectID   `json:"targetTableId"`
+	SourceTableIDs []primitive.ObjectID `json:"sourceTableIds"`
+}
+
+// TableFlaggedEvent is the message sent over WebSocket when a table is flagged for abuse
+type TableFlaggedEvent struct {
+	Type        string             `json:"type"`
+	BranchID    primitive.ObjectID `json:"branchId"`
+	TableID     primitive.ObjectID `json:"tableId"`
+	TableNumber int                `json:"tableNumber"`
+	TableLabel  string             `json:"tableLabel,omitempty"`
+	Reason      string             `json:"reason"`
+	FlaggedAt   time.Time          `json:"flaggedAt"`
+}
+
+// RequestFilter represents the optional query filters for request listings
+type RequestFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending attended cancelled"`
+	Search string `form:"q" binding:"omitempty,max=100"`
+	ZoneID string `form:"zoneId" binding:"omitempty,mongodb"`
+}
+
+// PendingRequestFilter represents the optional query filters for pending request listings
+type PendingRequestFilter struct {
+	ZoneID string `form:"zoneId" binding:"omitempty,mongodb"`
+}
+
+// RequestStatsFilter represents the optional date range for request stats
+type RequestStatsFilter struct {
+	From time.Time `form:"from" time_format:"2006-01-02"`
+	To   time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+// RequestCount is the number of requests sharing a status and payment method
+type RequestCount struct {
+	Status        RequestStatus
+	PaymentMethod PaymentMethod
+	Count         int64
+}
+
+// RequestStats aggregates request counts across the hot and archived collections
+type RequestStats struct {
+	Total           int64                   `json:"total"`
+	Archived        int64                   `json:"archived"`
+	ByStatus        map[RequestStatus]int64 `json:"byStatus"`
+	ByPaymentMethod map[PaymentMethod]int64 `json:"byPaymentMethod"`
+}
+
+// ArchivedRequest is the compact copy of a request kept after the retention
+// period. Diner notes and table details are stripped; only what analytics need stays.
+type ArchivedRequest struct {
+	ID            primitive.ObjectID `bson:"_id" json:"id"`
+	RestaurantID  primitive.ObjectID `bson:"restaurantId" json:"restaurantId"`
+	BranchID      primitive.ObjectID `bson:"branchId" json:"branchId"`
+	TableID       primitive.ObjectID `bson:"tableId" json:"tableId"`
+	Type          RequestType        `bson:"type" json:"type"`
+	PaymentMethod PaymentMethod      `bson:"paymentMethod" json:"paymentMethod,omitempty"`
+	Status        RequestStatus      `bson:"status" json:"status"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	ArchivedAt    time.Time          `bson:"archivedAt" json:"archivedAt"`
+}
+
+// TableQRParams are the params carried by a scanned table QR code.
+// Current codes only carry the table token (k) and, once the table's QR has
+// been rotated, its version (v); codes printed before tokens carry the
+// restaurant, branch and table IDs and the table number instead.
+type TableQRParams struct {
+	TableToken   string `json:"tableToken,omitempty" form:"k"`
+	QRVersion    int    `json:"qrVersion,omitempty" form:"v" binding:"omitempty,min=0"`
+	RestaurantID string `json:"restaurantId,omitempty" form:"r"`
+	BranchID     string `json:"branchId,omitempty" form:"b"`
+	TableID      string `json:"tableId,omitempty" form:"t"`
+	TableNumber  int    `json:"tableNumber,omitempty" form:"n" binding:"omitempty,min=1"`
+	Hash         string `json:"hash" form:"h" binding:"required"`
+}
+
 // VenueInfoInput represents the QR params used to look up public venue info.
 type VenueInfoInput struct {
-	RestaurantID string `form:"r" binding:"required"`
-	BranchID     string `form:"b" binding:"required"`
-	TableID      string `form:"t" binding:"required"`
-	TableNumber  int    `form:"n" binding:"required,min=1"`
-	Hash         string `form:"h" binding:"required"`
+	TableQRParams
+	// UserAgent is set by the handler from the request header, for scan analytics
+	UserAgent string `form:"-"`
 }
 
 // VenueInfo is the public information shown to a diner after scanning a table QR.
@@ -66,18 +220,51 @@ type VenueInfo struct {
 	RestaurantName string `json:"restaurantName"`
 	BranchAddress  string `json:"branchAddress"`
 	TableNumber    int    `json:"tableNumber"`
+	TableLabel     string `json:"tableLabel,omitempty"`
+	TableCapacity  int    `json:"tableCapacity,omitempty"`
+	// IsOpen tells whether the branch takes requests now; NextOpening is set while it's closed
+	IsOpen      bool       `json:"isOpen"`
+	NextOpening *time.Time `json:"nextOpening,omitempty"`
+	// PaymentMethods and RequestTypes are the options the diner page can offer
+	PaymentMethods []string `json:"paymentMethods"`
+	RequestTypes   []string `json:"requestTypes"`
+	// LocationRequired tells the diner page to send the browser geolocation with requests
+	LocationRequired bool `json:"locationRequired"`
+}
+
+// ShortLinkInput represents a scanned table QR short link
+type ShortLinkInput struct {
+	Slug string
+	// VenueInfoOnly is set for clients that get the venue info instead of being
+	// redirected to the request page, which would have looked it up itself
+	VenueInfoOnly bool
+	// UserAgent is set by the handler from the request header, for scan analytics
+	UserAgent string
+}
+
+// ShortLinkResolution is the result of resolving a table QR short link
+type ShortLinkResolution struct {
+	// RequestURL is the frontend request page with the table's signed QR params
+	RequestURL string     `json:"requestUrl"`
+	VenueInfo  *VenueInfo `json:"venueInfo"`
 }
 
 // NewRequest creates a new request
-func NewRequest(restaurantID, branchID, tableID primitive.ObjectID, tableNumber int, paymentMethod PaymentMethod) *Request {
+func NewRequest(restaurantID, branchID primitive.ObjectID, table TableRef, requestType RequestType, paymentMethod PaymentMethod, note string) *Request {
 	now := time.Now()
 	return &Request{
 		ID:            primitive.NewObjectID(),
 		RestaurantID:  restaurantID,
 		BranchID:      branchID,
-		TableID:       tableID,
-		TableNumber:   tableNumber,
+		TableID:       table.ID,
+		TableNumber:   table.Number,
+		TableLabel:    table.Label,
+		TableCapacity: table.Capacity,
+		ZoneID:        table.ZoneID,
+		Type:          requestType,
 		PaymentMethod: paymentMethod,
+		Note:          note,
+		Priority:      table.Priority,
 		Status:        StatusPending,
 		CreatedAt:     now,
 		UpdatedAt:     now,
@@ -89,3 +276,60 @@ func (r *Request) UpdateStatus(status RequestStatus) {
 	r.Status = status
 	r.UpdatedAt = time.Now()
 }
+
+// NewArchivedRequest builds the anonymized archive copy of a request
+func NewArchivedRequest(request *Request) *ArchivedRequest {
+	return &ArchivedRequest{
+		ID:            request.ID,
+		RestaurantID:  request.RestaurantID,
+		BranchID:      request.BranchID,
+		TableID:       request.TableID,
+		Type:          request.Type,
+		PaymentMethod: request.PaymentMethod,
+		Status:        request.Status,
+		CreatedAt:     request.CreatedAt,
+		ArchivedAt:    time.Now(),
+	}
+}
+
+// MoveToTable reassigns the request to another table, taking over its details, zone and priority
+func (r *Request) MoveToTable(table TableRef) {
+	r.TableID = table.ID
+	r.TableNumber = table.Number
+	r.TableLabel = table.Label
+	r.TableCapacity = table.Capacity
+	r.ZoneID = table.ZoneID
+	r.Priority = table.Priority
+	r.UpdatedAt = time.Now()
+}
+
+func NewRequestTransferredEvent(request *Request, from TableRef) *RequestTransferredEvent {
+	return &RequestTransferredEvent{
+		Type:            EventRequestTransferred,
+		Request:         request,
+		FromTableID:     from.ID,
+		FromTableNumber: from.Number,
+		FromTableLabel:  from.Label,
+	}
+}
+
+func NewTablesMergeEvent(eventType string, branchID, targetTableID primitive.ObjectID, sourceTableIDs []primitive.ObjectID) *TablesMergeEvent {
+	return &TablesMergeEvent{
+		Type:           eventType,
+		BranchID:       branchID,
+		TargetTableID:  targetTableID,
+		SourceTableIDs: sourceTableIDs,
+	}
+}
+
+func NewTableFlaggedEvent(branchID primitive.ObjectID, table TableRef, reason string, flaggedAt time.Time) *TableFlaggedEvent {
+	return &TableFlaggedEvent{
+		Type:        EventTableFlagged,
+		BranchID:    branchID,
+		TableID:     table.ID,
+		TableNumber: table.Number,
+		TableLabel:  table.Label,
+		Reason:      reason,
+		FlaggedAt:   flaggedAt,
+	}
+}
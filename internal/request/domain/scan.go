@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// User agent classes recorded with each scan
+const (
+	UserAgentIOS     = "ios"
+	UserAgentAndroid = "android"
+	UserAgentDesktop = "desktop"
+	UserAgentBot     = "bot"
+	UserAgentOther   = "other"
+)
+
+// ClassifyUserAgent maps a User-Agent header to one of the user agent classes
+func ClassifyUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case ua == "":
+		return UserAgentOther
+	case strings.Contains(ua, "bot"), strings.Contains(ua, "crawler"), strings.Contains(ua, "spider"),
+		strings.Contains(ua, "curl"), strings.Contains(ua, "wget"), strings.Contains(ua, "python"):
+		return UserAgentBot
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		return UserAgentIOS
+	case strings.Contains(ua, "android"):
+		return UserAgentAndroid
+	case strings.Contains(ua, "windows"), strings.Contains(ua, "macintosh"), strings.Contains(ua, "linux"), strings.Contains(ua, "cros"):
+		return UserAgentDesktop
+	default:
+		return UserAgentOther
+	}
+}
+
+// ScanEvent is a single scan of a table QR code
+type ScanEvent struct {
+	At             time.Time `bson:"at" json:"at"`
+	UserAgentClass string    `bson:"ua" json:"userAgentClass"`
+}
+
+// ScanBucket groups the scans of a table during one hour, so a busy table adds
+// to a single document instead of one document per scan. Only counters are
+// kept, so a bucket stays the same size however many scans it gets.
+type ScanBucket struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RestaurantID primitive.ObjectID `bson:"restaurantId" json:"restaurantId"`
+	BranchID     primitive.ObjectID `bson:"branchId" json:"branchId"`
+	TableID      primitive.ObjectID `bson:"tableId" json:"tableId"`
+	Hour         time.Time          `bson:"hour" json:"hour"`
+	Count        int64              `bson:"count" json:"count"`
+	// ByUserAgent counts the scans per user agent class
+	ByUserAgent map[string]int64 `bson:"byUserAgent" json:"byUserAgent"`
+	// Events is only set on buckets written before the per-class counters
+	Events []ScanEvent `bson:"events,omitempty" json:"events,omitempty"`
+}
+
+// ScanCount is the number of scans of a table on a day (UTC) from a user agent class
+type ScanCount struct {
+	Date           string
+	TableID        primitive.ObjectID
+	UserAgentClass string
+	Count          int64
+}
+
+// TableDayCount is the number of requests created for a table on a day (UTC)
+type TableDayCount struct {
+	Date    string
+	TableID primitive.ObjectID
+	Count   int64
+}
+
+// ScanReportFilter represents the date range and optional branch of a scan report
+type ScanReportFilter struct {
+	From     time.Time `form:"from" time_format:"2006-01-02"`
+	To       time.Time `form:"to" time_format:"2006-01-02"`
+	BranchID string    `form:"branchId"`
+}
+
+// TableScanStats are the scans and bill requests of a table
+type TableScanStats struct {
+	TableID        primitive.ObjectID `json:"tableId"`
+	TableNumber    int                `json:"tableNumber"`
+	Scans          int64              `json:"scans"`
+	Requests       int64              `json:"requests"`
+	ConversionRate float64            `json:"conversionRate"`
+}
+
+// ScanDay reports the scans and bill requests of one day (UTC)
+type ScanDay struct {
+	Date           string           `json:"date"`
+	Scans          int64            `json:"scans"`
+	Requests       int64            `json:"requests"`
+	ConversionRate float64          `json:"conversionRate"`
+	BusiestTables  []TableScanStats `json:"busiestTables"`
+}
+
+// ScanReport reports how many QR scans turned into bill requests over a date range
+type ScanReport struct {
+	From           string           `json:"from"`
+	To             string           `json:"to"`
+	Scans          int64            `json:"scans"`
+	Requests       int64            `json:"requests"`
+	ConversionRate float64          `json:"conversionRate"`
+	ByUserAgent    map[string]int64 `json:"byUserAgent"`
+	Days           []ScanDay        `json:"days"`
+}
+
+// ConversionRate returns requests/scans, or 0 when there were no scans
+func ConversionRate(scans, requests int64) float64 {
+	if scans == 0 {
+		return 0
+	}
+	return float64(requests) / float64(scans)
+}
@@ -54,14 +54,16 @@ type Handler struct {
 	useCase    usecase.UseCase
 	hub        *pkg.Hub
 	jwtService *pkg.JWTService
+	ipLimiter  *pkg.SlidingWindowLimiter
 }
 
 // NewRequestHandler creates a new request handler
-func NewRequestHandler(useCase usecase.UseCase, hub *pkg.Hub, jwtService *pkg.JWTService) *Handler {
+func NewRequestHandler(useCase usecase.UseCase, hub *pkg.Hub, jwtService *pkg.JWTService, ipLimiter *pkg.SlidingWindowLimiter) *Handler {
 	return &Handler{
 		useCase:    useCase,
 		hub:        hub,
 		jwtService: jwtService,
+		ipLimiter:  ipLimiter,
 	}
 }
 
@@ -73,6 +75,9 @@ func NewRequestHandler(useCase usecase.UseCase, hub *pkg.Hub, jwtService *pkg.JW
 // @Param input body domain.CreateRequestInput true \"Request data\"
 // @Success 201 {object} pkg.Response{data=domain.Request}
 // @Failure 400 {object} pkg.Response
+// @Failure 403 {object} pkg.Response
+// @Failure 410 {object} pkg.Response
+// @Failure 429 {object} pkg.Response
 // @Failure 500 {object} pkg.Response
 // @Router /api/v1/public/request-account [post]
 func (h *Handler) Create(c *gin.Context) {
@@ -92,6 +97,30 @@ func (h *Handler) Create(c *gin.Context) {
 			pkg.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
 			return
 		}
+		if errors.Is(err, pkg.ErrTableBlocked) {
+			pkg.ForbiddenResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrBranchClosed) {
+			pkg.ForbiddenResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrOutsideGeofence) {
+			pkg.ForbiddenResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrTooManyRequests) {
+			pkg.ErrorResponse(c, http.StatusTooManyRequests, "Too many requests for this table, please try again later", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrQRCodeRevoked) {
+			pkg.ErrorResponse(c, http.StatusGone, err.Error(), err)
+			return
+		}
 		pkg.BadRequestResponse(c, "Failed to create request", err)
 		return
 	}
@@ -103,14 +132,18 @@ func (h *Handler) Create(c *gin.Context) {
 // @Summary Get public venue info for a table QR
 // @Tags requests
 // @Produce json
-// @Param r query string true "Restaurant ID"
-// @Param b query string true "Branch ID"
-// @Param t query string true "Table ID"
-// @Param n query int true "Table number"
+// @Param k query string false "Table token"
+// @Param v query int false "QR version"
+// @Param r query string false "Restaurant ID (legacy QR codes)"
+// @Param b query string false "Branch ID (legacy QR codes)"
+// @Param t query string false "Table ID (legacy QR codes)"
+// @Param n query int false "Table number (legacy QR codes)"
 // @Param h query string true "QR hash"
 // @Success 200 {object} pkg.Response{data=domain.VenueInfo}
 // @Failure 400 {object} pkg.Response
 // @Failure 404 {object} pkg.Response
+// @Failure 410 {object} pkg.Response
+// @Failure 429 {object} pkg.Response
 // @Router /api/v1/public/venue-info [get]
 func (h *Handler) GetVenueInfo(c *gin.Context) {
 	var input domain.VenueInfoInput
@@ -118,11 +151,20 @@ func (h *Handler) GetVenueInfo(c *gin.Context) {
 		pkg.BadRequestResponse(c, "Invalid input", err)
 		return
 	}
+	input.UserAgent = c.Request.UserAgent()
 
 	info, err := h.useCase.GetVenueInfo(c.Request.Context(), input)
 	if err != nil {
 		if errors.Is(err, pkg.ErrNotFound) {
-			pkg.NotFoundResponse(c, "Restaurant or branch not found", err)
+			pkg.NotFoundResponse(c, "Restaurant, branch or table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrTooManyRequests) {
+			pkg.ErrorResponse(c, http.StatusTooManyRequests, "Too many requests for this table, please try again later", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrQRCodeRevoked) {
+			pkg.ErrorResponse(c, http.StatusGone, err.Error(), err)
 			return
 		}
 		pkg.BadRequestResponse(c, "Failed to get venue info", err)
@@ -132,6 +174,52 @@ func (h *Handler) GetVenueInfo(c *gin.Context) {
 	pkg.SuccessResponse(c, http.StatusOK, "Venue info retrieved successfully", info)
 }
 
+// ResolveShortLink handles a scanned table QR short link. Browsers are redirected
+// to the frontend request page; clients asking for JSON get the venue info.
+// @Summary Resolve a table QR short link
+// @Tags requests
+// @Produce json
+// @Param slug path string true "Short link slug"
+// @Param format query string false "Set to json to get the venue info instead of a redirect"
+// @Success 200 {object} pkg.Response{data=domain.ShortLinkResolution}
+// @Success 302
+// @Failure 404 {object} pkg.Response
+// @Failure 410 {object} pkg.Response
+// @Failure 429 {object} pkg.Response
+// @Router /q/{slug} [get]
+func (h *Handler) ResolveShortLink(c *gin.Context) {
+	input := domain.ShortLinkInput{
+		Slug:          c.Param("slug"),
+		VenueInfoOnly: c.Query("format") == "json" || c.NegotiateFormat(gin.MIMEHTML, gin.MIMEJSON) == gin.MIMEJSON,
+		UserAgent:     c.Request.UserAgent(),
+	}
+
+	resolution, err := h.useCase.ResolveShortLink(c.Request.Context(), input)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "QR code not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrQRCodeRevoked) {
+			pkg.ErrorResponse(c, http.StatusGone, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrTooManyRequests) {
+			pkg.ErrorResponse(c, http.StatusTooManyRequests, "Too many requests for this table, please try again later", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to resolve QR code", err)
+		return
+	}
+
+	if input.VenueInfoOnly {
+		pkg.SuccessResponse(c, http.StatusOK, "QR code resolved successfully", resolution)
+		return
+	}
+
+	c.Redirect(http.StatusFound, resolution.RequestURL)
+}
+
 // GetByID handles retrieving a request by ID
 // @Summary Get request by ID
 // @Tags requests
@@ -187,6 +275,9 @@ func (h *Handler) GetByID(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param restaurantId path string true \"Restaurant ID\"
+// @Param status query string false \"Filter by status\"
+// @Param q query string false \"Search in diner notes\"
+// @Param zoneId query string false \"Only requests of tables in this zone\"
 // @Success 200 {object} pkg.Response{data=[]domain.Request}
 // @Failure 400 {object} pkg.Response
 // @Failure 401 {object} pkg.Response
@@ -212,7 +303,13 @@ func (h *Handler) ListByRestaurant(c *gin.Context) {
 		return
 	}
 
-	requests, err := h.useCase.GetByRestaurantID(c.Request.Context(), restaurantID, userID, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	var filter domain.RequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		pkg.BadRequestResponse(c, "Invalid filters", err)
+		return
+	}
+
+	requests, err := h.useCase.GetByRestaurantID(c.Request.Context(), restaurantID, userID, filter, extractRestaurantIDHint(c), extractBranchIDHint(c))
 	if err != nil {
 		if errors.Is(err, pkg.ErrNotFound) {
 			pkg.NotFoundResponse(c, "Restaurant not found", err)
@@ -235,6 +332,7 @@ func (h *Handler) ListByRestaurant(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param restaurantId path string true \"Restaurant ID\"
+// @Param zoneId query string false \"Only requests of tables in this zone\"
 // @Success 200 {object} pkg.Response{data=[]domain.Request}
 // @Failure 400 {object} pkg.Response
 // @Failure 401 {object} pkg.Response
@@ -260,7 +358,13 @@ func (h *Handler) ListPendingByRestaurant(c *gin.Context) {
 		return
 	}
 
-	requests, err := h.useCase.GetPendingByRestaurantID(c.Request.Context(), restaurantID, userID, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	var filter domain.PendingRequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		pkg.BadRequestResponse(c, "Invalid filters", err)
+		return
+	}
+
+	requests, err := h.useCase.GetPendingByRestaurantID(c.Request.Context(), restaurantID, userID, filter, extractRestaurantIDHint(c), extractBranchIDHint(c))
 	if err != nil {
 		if errors.Is(err, pkg.ErrNotFound) {
 			pkg.NotFoundResponse(c, "Restaurant not found", err)
@@ -277,6 +381,124 @@ func (h *Handler) ListPendingByRestaurant(c *gin.Context) {
 	pkg.SuccessResponse(c, http.StatusOK, "Pending requests retrieved successfully", requests)
 }
 
+// GetStats handles retrieving aggregate request counts for a restaurant
+// @Summary Get request stats by restaurant (includes archived requests)
+// @Tags requests
+// @Produce json
+// @Security BearerAuth
+// @Param restaurantId path string true \"Restaurant ID\"
+// @Param from query string false \"From date (YYYY-MM-DD)\"
+// @Param to query string false \"To date, inclusive (YYYY-MM-DD)\"
+// @Success 200 {object} pkg.Response{data=domain.RequestStats}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/requests/restaurant/{restaurantId}/stats [get]
+func (h *Handler) GetStats(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	restaurantIDStr := c.Param("restaurantId")
+	restaurantID, err := primitive.ObjectIDFromHex(restaurantIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid restaurant ID", err)
+		return
+	}
+
+	var filter domain.RequestStatsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		pkg.BadRequestResponse(c, "Invalid filters", err)
+		return
+	}
+
+	stats, err := h.useCase.GetStats(c.Request.Context(), restaurantID, userID, filter, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Restaurant not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) || errors.Is(err, pkg.ErrForbidden) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this restaurant", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to get request stats", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Request stats retrieved successfully", stats)
+}
+
+// GetScanReport handles retrieving QR scan analytics for a restaurant
+// @Summary Get QR scan report by restaurant (scans, conversion to requests, busiest tables per day)
+// @Tags requests
+// @Produce json
+// @Security BearerAuth
+// @Param restaurantId path string true \"Restaurant ID\"
+// @Param from query string false \"From date (YYYY-MM-DD, default 29 days before to)\"
+// @Param to query string false \"To date, inclusive (YYYY-MM-DD, default today)\"
+// @Param branchId query string false \"Only this branch\"
+// @Success 200 {object} pkg.Response{data=domain.ScanReport}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/requests/restaurant/{restaurantId}/scans [get]
+func (h *Handler) GetScanReport(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	restaurantIDStr := c.Param("restaurantId")
+	restaurantID, err := primitive.ObjectIDFromHex(restaurantIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid restaurant ID", err)
+		return
+	}
+
+	var filter domain.ScanReportFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		pkg.BadRequestResponse(c, "Invalid filters", err)
+		return
+	}
+
+	report, err := h.useCase.GetScanReport(c.Request.Context(), restaurantID, userID, filter)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Restaurant or branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this restaurant", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to get scan report", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Scan report retrieved successfully", report)
+}
+
 // UpdateStatus handles updating request status
 // @Summary Update request status
 // @Tags requests
@@ -334,6 +556,228 @@ func (h *Handler) UpdateStatus(c *gin.Context) {
 	pkg.SuccessResponse(c, http.StatusOK, "Request status updated successfully", request)
 }
 
+// Transfer handles moving a pending request to another table
+// @Summary Transfer request to another table
+// @Tags requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true \"Request ID\"
+// @Param input body domain.TransferRequestInput true \"Target table\"
+// @Success 200 {object} pkg.Response{data=domain.Request}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 409 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/requests/{id}/table [put]
+func (h *Handler) Transfer(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	requestIDStr := c.Param("id")
+	requestID, err := primitive.ObjectIDFromHex(requestIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid request ID", err)
+		return
+	}
+
+	var input domain.TransferRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	request, err := h.useCase.Transfer(c.Request.Context(), requestID, userID, input, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Request or table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) || errors.Is(err, pkg.ErrForbidden) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this request", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrRequestNotPending) {
+			pkg.ErrorResponse(c, http.StatusConflict, "Only pending requests can be transferred", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrRequestAlreadyPending) {
+			pkg.ErrorResponse(c, http.StatusConflict, "The target table already has a pending request", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to transfer request", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Request transferred successfully", request)
+}
+
+// MergeTables handles merging tables into a target table
+// @Summary Merge tables
+// @Description Pending requests on the source tables move to the target table. If the target already has one, the extra requests are cancelled.
+// @Tags requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.MergeTablesInput true \"Tables to merge\"
+// @Success 200 {object} pkg.Response{data=domain.MergeTablesResult}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/requests/tables/merge [post]
+func (h *Handler) MergeTables(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	var input domain.MergeTablesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	result, err := h.useCase.MergeTables(c.Request.Context(), userID, input, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) || errors.Is(err, pkg.ErrForbidden) {
+			pkg.UnauthorizedResponse(c, "You don't have access to these tables", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to merge tables", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Tables merged successfully", result)
+}
+
+// UnmergeTables handles releasing the tables merged into a target table
+// @Summary Unmerge tables
+// @Tags requests
+// @Produce json
+// @Security BearerAuth
+// @Param tableId path string true \"Target table ID\"
+// @Success 200 {object} pkg.Response
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/requests/tables/{tableId}/unmerge [post]
+func (h *Handler) UnmergeTables(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	tableIDStr := c.Param("tableId")
+	tableID, err := primitive.ObjectIDFromHex(tableIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid table ID", err)
+		return
+	}
+
+	released, err := h.useCase.UnmergeTables(c.Request.Context(), tableID, userID, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) || errors.Is(err, pkg.ErrForbidden) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this table", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to unmerge tables", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Tables unmerged successfully", gin.H{"releasedTableIds": released})
+}
+
+// GetFloorPlan handles retrieving a branch's live floor plan
+// @Summary Get the live floor plan of a branch
+// @Description Returns every table of the branch with its floor plan layout and pending request, if any.
+// @Tags requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true \"Branch ID\"
+// @Success 200 {object} pkg.Response{data=domain.FloorPlan}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/branches/{id}/floor-plan [get]
+func (h *Handler) GetFloorPlan(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	plan, err := h.useCase.GetFloorPlan(c.Request.Context(), branchID, userID, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) || errors.Is(err, pkg.ErrForbidden) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to retrieve floor plan", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Floor plan retrieved successfully", plan)
+}
+
 // Delete handles request deletion
 // @Summary Delete request
 // @Tags requests
@@ -387,6 +831,7 @@ func (h *Handler) Delete(c *gin.Context) {
 // @Tags requests
 // @Param restaurantId path string true \"Restaurant ID\"
 // @Param token query string true \"JWT Token\"
+// @Param zoneId query string false \"Only receive events of tables in this zone\"
 // @Router /api/v1/requests/ws/{restaurantId} [get]
 func (h *Handler) WebSocket(c *gin.Context) {
 	// Extract token from query parameter
@@ -410,6 +855,17 @@ func (h *Handler) WebSocket(c *gin.Context) {
 		return
 	}
 
+	// Optional zone subscription, e.g. for a waiter who only serves the terrace
+	var zoneID *primitive.ObjectID
+	if zoneIDStr := c.Query("zoneId"); zoneIDStr != "" {
+		id, err := primitive.ObjectIDFromHex(zoneIDStr)
+		if err != nil {
+			pkg.BadRequestResponse(c, "Invalid zone ID", err)
+			return
+		}
+		zoneID = &id
+	}
+
 	// Upgrade HTTP connection to WebSocket
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -421,6 +877,7 @@ func (h *Handler) WebSocket(c *gin.Context) {
 	client := &pkg.Client{
 		ID:           uuid.New().String(),
 		RestaurantID: restaurantID,
+		ZoneID:       zoneID,
 		UserID:       claims.UserID,
 		Conn:         conn,
 		Send:         make(chan []byte, 256),
@@ -437,8 +894,8 @@ func (h *Handler) WebSocket(c *gin.Context) {
 // RegisterRoutes registers all request routes
 func (h *Handler) RegisterRoutes(router *gin.RouterGroup, publicRouter *gin.RouterGroup) {
 	// Public routes (no authentication required)
-	publicRouter.POST("/request-account", h.Create)
-	publicRouter.GET("/venue-info", h.GetVenueInfo)
+	publicRouter.POST("/request-account", middleware.RateLimitByIP(h.ipLimiter), h.Create)
+	publicRouter.GET("/venue-info", middleware.RateLimitByIP(h.ipLimiter), h.GetVenueInfo)
 
 	// Protected routes (authentication required)
 	requests := router.Group("/requests")
@@ -446,9 +903,23 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup, publicRouter *gin.Rout
 		requests.GET("/:id", h.GetByID)
 		requests.GET("/restaurant/:restaurantId", h.ListByRestaurant)
 		requests.GET("/restaurant/:restaurantId/pending", h.ListPendingByRestaurant)
+		requests.GET("/restaurant/:restaurantId/stats", h.GetStats)
+		requests.GET("/restaurant/:restaurantId/scans", middleware.OwnerOnly(), h.GetScanReport)
 		requests.PUT("/:id/status", h.UpdateStatus)
+		requests.PUT("/:id/table", h.Transfer)
+		requests.POST("/tables/merge", h.MergeTables)
+		requests.POST("/tables/:tableId/unmerge", h.UnmergeTables)
 		requests.DELETE("/:id", h.Delete)
 	}
+
+	// The live floor plan lives under /branches next to the one saved by the table module
+	router.GET("/branches/:id/floor-plan", h.GetFloorPlan)
+}
+
+// RegisterShortLinkRoute registers the public QR short link resolver.
+// It lives outside /api/v1 to keep the URLs printed in QR codes short.
+func (h *Handler) RegisterShortLinkRoute(router gin.IRoutes) {
+	router.GET("/q/:slug", middleware.RateLimitByIP(h.ipLimiter), h.ResolveShortLink)
 }
 
 // RegisterWebSocketRoute registers the WebSocket route (without auth middleware)
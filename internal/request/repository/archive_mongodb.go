@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"juansecalvinio/tepidolacuenta/internal/request/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// duplicateKeyCode is the MongoDB error code of a unique index violation
+const duplicateKeyCode = 11000
+
+type mongoArchiveRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoArchiveRepository creates a new MongoDB repository for archived requests
+func NewMongoArchiveRepository(db *mongo.Database) ArchiveRepository {
+	return &mongoArchiveRepository{
+		collection: db.Collection("requests_archive"),
+	}
+}
+
+// InsertMany stores archived requests. Archived copies keep the original _id,
+// so re-archiving a batch after a partial failure is a no-op for the
+// documents that already made it. Any other write error is returned.
+func (r *mongoArchiveRepository) InsertMany(ctx context.Context, requests []*domain.ArchivedRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(requests))
+	for _, request := range requests {
+		docs = append(docs, request)
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeyErrors(err) {
+		return err
+	}
+	return nil
+}
+
+// onlyDuplicateKeyErrors reports whether every failed insert of a bulk write was
+// a duplicate key. mongo.IsDuplicateKeyError is true as soon as one of them is,
+// which would hide the others.
+func onlyDuplicateKeyErrors(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) {
+		return false
+	}
+	if bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != duplicateKeyCode {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *mongoArchiveRepository) CountGrouped(ctx context.Context, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, from, to time.Time) ([]domain.RequestCount, error) {
+	return countGrouped(ctx, r.collection, restaurantID, branchID, from, to)
+}
+
+func (r *mongoArchiveRepository) CountByTableAndDay(ctx context.Context, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, requestType domain.RequestType, from, to time.Time) ([]domain.TableDayCount, error) {
+	return countByTableAndDay(ctx, r.collection, restaurantID, branchID, requestType, from, to)
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"regexp"
+	"time"
 
 	"juansecalvinio/tepidolacuenta/internal/pkg"
 	"juansecalvinio/tepidolacuenta/internal/request/domain"
@@ -40,8 +42,9 @@ func (r *mongoRepository) FindByID(ctx context.Context, id primitive.ObjectID) (
 	return &request, nil
 }
 
-func (r *mongoRepository) FindByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID) ([]*domain.Request, error) {
+func (r *mongoRepository) FindByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, requestFilter domain.RequestFilter) ([]*domain.Request, error) {
 	filter := bson.M{"restaurantId": restaurantID}
+	applyRequestFilter(filter, requestFilter)
 	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
@@ -59,12 +62,39 @@ func (r *mongoRepository) FindByRestaurantID(ctx context.Context, restaurantID p
 	return requests, nil
 }
 
-func (r *mongoRepository) FindPendingByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID) ([]*domain.Request, error) {
+// applyRequestFilter adds the optional list filters to a Mongo query.
+// The note search is a case-insensitive substring match.
+func applyRequestFilter(filter bson.M, requestFilter domain.RequestFilter) {
+	if requestFilter.Status != "" {
+		filter["status"] = requestFilter.Status
+	}
+	if requestFilter.Search != "" {
+		filter["note"] = bson.M{
+			"$regex":   regexp.QuoteMeta(requestFilter.Search),
+			"$options": "i",
+		}
+	}
+	applyZoneFilter(filter, requestFilter.ZoneID)
+}
+
+// applyZoneFilter restricts a Mongo query to the requests of one zone.
+// zoneID is validated when binding the filter; an empty one matches every zone.
+func applyZoneFilter(filter bson.M, zoneID string) {
+	if id, err := primitive.ObjectIDFromHex(zoneID); err == nil {
+		filter["zoneId"] = id
+	}
+}
+
+// pendingSort puts priority tables first, then the requests that have waited the longest
+var pendingSort = bson.D{{Key: "priority", Value: -1}, {Key: "createdAt", Value: 1}}
+
+func (r *mongoRepository) FindPendingByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, pendingFilter domain.PendingRequestFilter) ([]*domain.Request, error) {
 	filter := bson.M{
 		"restaurantId": restaurantID,
 		"status":       domain.StatusPending,
 	}
-	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	applyZoneFilter(filter, pendingFilter.ZoneID)
+	opts := options.Find().SetSort(pendingSort)
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
@@ -81,8 +111,9 @@ func (r *mongoRepository) FindPendingByRestaurantID(ctx context.Context, restaur
 	return requests, nil
 }
 
-func (r *mongoRepository) FindByBranchID(ctx context.Context, branchID primitive.ObjectID) ([]*domain.Request, error) {
+func (r *mongoRepository) FindByBranchID(ctx context.Context, branchID primitive.ObjectID, requestFilter domain.RequestFilter) ([]*domain.Request, error) {
 	filter := bson.M{"branchId": branchID}
+	applyRequestFilter(filter, requestFilter)
 	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
@@ -99,12 +130,13 @@ func (r *mongoRepository) FindByBranchID(ctx context.Context, branchID primitive
 	return requests, nil
 }
 
-func (r *mongoRepository) FindPendingByBranchID(ctx context.Context, branchID primitive.ObjectID) ([]*domain.Request, error) {
+func (r *mongoRepository) FindPendingByBranchID(ctx context.Context, branchID primitive.ObjectID, pendingFilter domain.PendingRequestFilter) ([]*domain.Request, error) {
 	filter := bson.M{
 		"branchId": branchID,
 		"status":   domain.StatusPending,
 	}
-	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	applyZoneFilter(filter, pendingFilter.ZoneID)
+	opts := options.Find().SetSort(pendingSort)
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
@@ -120,9 +152,10 @@ func (r *mongoRepository) FindPendingByBranchID(ctx context.Context, branchID pr
 	return requests, nil
 }
 
-func (r *mongoRepository) ExistsPendingForTable(ctx context.Context, tableID primitive.ObjectID) (bool, error) {
+func (r *mongoRepository) ExistsPendingForTable(ctx context.Context, tableID primitive.ObjectID, requestType domain.RequestType) (bool, error) {
 	filter := bson.M{
 		"tableId": tableID,
+		"type":    requestType,
 		"status":  domain.StatusPending,
 	}
 	count, err := r.collection.CountDocuments(ctx, filter)
@@ -132,9 +165,72 @@ func (r *mongoRepository) ExistsPendingForTable(ctx context.Context, tableID pri
 	return count > 0, nil
 }
 
+// FindPendingByTableID returns the pending requests of a table, at most one per type
+func (r *mongoRepository) FindPendingByTableID(ctx context.Context, tableID primitive.ObjectID) ([]*domain.Request, error) {
+	filter := bson.M{
+		"tableId": tableID,
+		"status":  domain.StatusPending,
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	requests := make([]*domain.Request, 0)
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// FindCreatedBefore returns up to limit requests of a restaurant created before the given time, oldest first.
+// Pending requests are left out, so diners' open requests stay on the dashboard however old they are.
+func (r *mongoRepository) FindCreatedBefore(ctx context.Context, restaurantID primitive.ObjectID, before time.Time, limit int64) ([]*domain.Request, error) {
+	filter := bson.M{
+		"restaurantId": restaurantID,
+		"createdAt":    bson.M{"$lt": before},
+		"status":       bson.M{"$ne": domain.StatusPending},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}).SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	requests := make([]*domain.Request, 0)
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+func (r *mongoRepository) CountGrouped(ctx context.Context, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, from, to time.Time) ([]domain.RequestCount, error) {
+	return countGrouped(ctx, r.collection, restaurantID, branchID, from, to)
+}
+
+func (r *mongoRepository) CountByTableAndDay(ctx context.Context, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, requestType domain.RequestType, from, to time.Time) ([]domain.TableDayCount, error) {
+	return countByTableAndDay(ctx, r.collection, restaurantID, branchID, requestType, from, to)
+}
+
 func (r *mongoRepository) Update(ctx context.Context, request *domain.Request) error {
 	filter := bson.M{"_id": request.ID}
 	update := bson.M{"$set": request}
+	// The zone and session are omitted from $set when empty, so moving to a table without them must clear them
+	unset := bson.M{}
+	if request.ZoneID == nil {
+		unset["zoneId"] = ""
+	}
+	if request.SessionID == nil {
+		unset["sessionId"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
@@ -160,3 +256,120 @@ func (r *mongoRepository) Delete(ctx context.Context, id primitive.ObjectID) err
 
 	return nil
 }
+
+func (r *mongoRepository) DeleteByIDs(ctx context.Context, ids []primitive.ObjectID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// countGrouped counts the requests of a collection grouped by status and payment method.
+// Shared by the hot and archive collections, which use the same field names.
+// Zero from/to values leave that side of the date range open.
+func countGrouped(ctx context.Context, collection *mongo.Collection, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, from, to time.Time) ([]domain.RequestCount, error) {
+	match := createdBetween(restaurantID, branchID, from, to)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"status": "$status", "paymentMethod": "$paymentMethod"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID struct {
+			Status        domain.RequestStatus `bson:"status"`
+			PaymentMethod domain.PaymentMethod `bson:"paymentMethod"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make([]domain.RequestCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, domain.RequestCount{
+			Status:        row.ID.Status,
+			PaymentMethod: row.ID.PaymentMethod,
+			Count:         row.Count,
+		})
+	}
+
+	return counts, nil
+}
+
+// countByTableAndDay counts the requests of a type in a collection per table and
+// day (UTC). Shared by the hot and archive collections, like countGrouped.
+func countByTableAndDay(ctx context.Context, collection *mongo.Collection, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, requestType domain.RequestType, from, to time.Time) ([]domain.TableDayCount, error) {
+	match := createdBetween(restaurantID, branchID, from, to)
+	match["type"] = requestType
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"date":    bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$createdAt"}},
+				"tableId": "$tableId",
+			},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID struct {
+			Date    string             `bson:"date"`
+			TableID primitive.ObjectID `bson:"tableId"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make([]domain.TableDayCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, domain.TableDayCount{
+			Date:    row.ID.Date,
+			TableID: row.ID.TableID,
+			Count:   row.Count,
+		})
+	}
+
+	return counts, nil
+}
+
+// createdBetween matches a restaurant's requests, optionally of one branch, created in [from, to).
+// Zero from/to values leave that side of the date range open.
+func createdBetween(restaurantID primitive.ObjectID, branchID *primitive.ObjectID, from, to time.Time) bson.M {
+	match := bson.M{"restaurantId": restaurantID}
+	if branchID != nil {
+		match["branchId"] = *branchID
+	}
+	createdAt := bson.M{}
+	if !from.IsZero() {
+		createdAt["$gte"] = from
+	}
+	if !to.IsZero() {
+		createdAt["$lt"] = to
+	}
+	if len(createdAt) > 0 {
+		match["createdAt"] = createdAt
+	}
+	return match
+}
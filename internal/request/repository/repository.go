@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"juansecalvinio/tepidolacuenta/internal/request/domain"
 
@@ -12,11 +13,30 @@ import (
 type Repository interface {
 	Create(ctx context.Context, request *domain.Request) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Request, error)
-	FindByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID) ([]*domain.Request, error)
-	FindPendingByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID) ([]*domain.Request, error)
-	FindByBranchID(ctx context.Context, branchID primitive.ObjectID) ([]*domain.Request, error)
-	FindPendingByBranchID(ctx context.Context, branchID primitive.ObjectID) ([]*domain.Request, error)
-	ExistsPendingForTable(ctx context.Context, tableID primitive.ObjectID) (bool, error)
+	FindByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, filter domain.RequestFilter) ([]*domain.Request, error)
+	FindPendingByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, filter domain.PendingRequestFilter) ([]*domain.Request, error)
+	FindByBranchID(ctx context.Context, branchID primitive.ObjectID, filter domain.RequestFilter) ([]*domain.Request, error)
+	FindPendingByBranchID(ctx context.Context, branchID primitive.ObjectID, filter domain.PendingRequestFilter) ([]*domain.Request, error)
+	ExistsPendingForTable(ctx context.Context, tableID primitive.ObjectID, requestType domain.RequestType) (bool, error)
+	FindPendingByTableID(ctx context.Context, tableID primitive.ObjectID) ([]*domain.Request, error)
+	FindCreatedBefore(ctx context.Context, restaurantID primitive.ObjectID, before time.Time, limit int64) ([]*domain.Request, error)
+	CountGrouped(ctx context.Context, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, from, to time.Time) ([]domain.RequestCount, error)
+	CountByTableAndDay(ctx context.Context, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, requestType domain.RequestType, from, to time.Time) ([]domain.TableDayCount, error)
 	Update(ctx context.Context, request *domain.Request) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteByIDs(ctx context.Context, ids []primitive.ObjectID) (int64, error)
+}
+
+// ArchiveRepository defines the interface for archived request persistence
+type ArchiveRepository interface {
+	InsertMany(ctx context.Context, requests []*domain.ArchivedRequest) error
+	CountGrouped(ctx context.Context, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, from, to time.Time) ([]domain.RequestCount, error)
+	CountByTableAndDay(ctx context.Context, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, requestType domain.RequestType, from, to time.Time) ([]domain.TableDayCount, error)
+}
+
+// ScanRepository defines the interface for QR scan event persistence
+type ScanRepository interface {
+	// Record adds a scan to the table's bucket for the hour it happened in
+	Record(ctx context.Context, restaurantID, branchID, tableID primitive.ObjectID, event domain.ScanEvent) error
+	CountByTableAndDay(ctx context.Context, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, from, to time.Time) ([]domain.ScanCount, error)
 }
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"juansecalvinio/tepidolacuenta/internal/request/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoScanRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoScanRepository creates a new MongoDB repository for QR scan events
+func NewMongoScanRepository(db *mongo.Database) ScanRepository {
+	return &mongoScanRepository{
+		collection: db.Collection("table_scans"),
+	}
+}
+
+func (r *mongoScanRepository) Record(ctx context.Context, restaurantID, branchID, tableID primitive.ObjectID, event domain.ScanEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	hour := event.At.UTC().Truncate(time.Hour)
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"tableId": tableID, "hour": hour},
+		bson.M{
+			"$setOnInsert": bson.M{"restaurantId": restaurantID, "branchId": branchID},
+			"$inc":         bson.M{"count": 1, "byUserAgent." + event.UserAgentClass: 1},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// CountByTableAndDay counts scans per table, day (UTC) and user agent class.
+// Buckets are matched by hour, so from and to should fall on hour boundaries.
+func (r *mongoScanRepository) CountByTableAndDay(ctx context.Context, restaurantID primitive.ObjectID, branchID *primitive.ObjectID, from, to time.Time) ([]domain.ScanCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	match := bson.M{"restaurantId": restaurantID, "hour": bson.M{"$gte": from, "$lt": to}}
+	if branchID != nil {
+		match["branchId"] = *branchID
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		// One {k: class, v: scans} pair per counter, plus one per event of buckets
+		// written before the counters
+		{{Key: "$project", Value: bson.M{
+			"hour":    1,
+			"tableId": 1,
+			"classes": bson.M{"$concatArrays": bson.A{
+				bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$byUserAgent", bson.M{}}}},
+				bson.M{"$map": bson.M{
+					"input": bson.M{"$ifNull": bson.A{"$events", bson.A{}}},
+					"as":    "event",
+					"in":    bson.M{"k": "$$event.ua", "v": 1},
+				}},
+			}},
+		}}},
+		{{Key: "$unwind", Value: "$classes"}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"date":    bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$hour"}},
+				"tableId": "$tableId",
+				"ua":      "$classes.k",
+			},
+			"count": bson.M{"$sum": "$classes.v"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID struct {
+			Date           string             `bson:"date"`
+			TableID        primitive.ObjectID `bson:"tableId"`
+			UserAgentClass string             `bson:"ua"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make([]domain.ScanCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, domain.ScanCount{
+			Date:           row.ID.Date,
+			TableID:        row.ID.TableID,
+			UserAgentClass: row.ID.UserAgentClass,
+			Count:          row.Count,
+		})
+	}
+
+	return counts, nil
+}
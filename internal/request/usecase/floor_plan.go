@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"juansecalvinio/tepidolacuenta/internal/request/domain"
+	sessionDomain "juansecalvinio/tepidolacuenta/internal/session/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetFloorPlan returns every table of a branch with its floor plan layout, open
+// session and pending request, so the dashboard can render a live map of the room
+func (uc *requestUseCase) GetFloorPlan(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.FloorPlan, error) {
+	branch, err := uc.branchRepo.FindByID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, branch.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRestaurantAccess(restaurant.ID, restaurant.UserID, userID, restaurantIDHint); err != nil {
+		return nil, err
+	}
+
+	if err := authorizeBranchScope(branch.ID, branchIDHint); err != nil {
+		return nil, err
+	}
+
+	tables, err := uc.tableRepo.FindByBranchID(ctx, branch.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Number < tables[j].Number })
+
+	pending, err := uc.repo.FindPendingByBranchID(ctx, branch.ID, domain.PendingRequestFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Pending requests come sorted by urgency, which each table's list keeps
+	byTable := make(map[primitive.ObjectID][]*domain.Request, len(pending))
+	for _, request := range pending {
+		byTable[request.TableID] = append(byTable[request.TableID], request)
+	}
+
+	sessions, err := uc.sessionRepo.FindByBranchID(ctx, branch.ID, sessionDomain.StatusOpen, 0)
+	if err != nil {
+		return nil, err
+	}
+	sessionByTable := make(map[primitive.ObjectID]*sessionDomain.TableSession, len(sessions))
+	for _, session := range sessions {
+		sessionByTable[session.TableID] = session
+	}
+
+	plan := &domain.FloorPlan{
+		BranchID: branch.ID,
+		Tables:   make([]*domain.FloorPlanTable, 0, len(tables)),
+	}
+	for _, table := range tables {
+		pendingRequests := byTable[table.ID]
+		if pendingRequests == nil {
+			pendingRequests = make([]*domain.Request, 0)
+		}
+		plan.Tables = append(plan.Tables, &domain.FloorPlanTable{
+			Table:           table,
+			Session:         sessionByTable[table.ID],
+			PendingRequests: pendingRequests,
+		})
+	}
+
+	return plan, nil
+}
@@ -3,12 +3,22 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
 
+	branchDomain "juansecalvinio/tepidolacuenta/internal/branch/domain"
 	branchRepo "juansecalvinio/tepidolacuenta/internal/branch/repository"
+	"juansecalvinio/tepidolacuenta/internal/database"
 	"juansecalvinio/tepidolacuenta/internal/pkg"
 	"juansecalvinio/tepidolacuenta/internal/request/domain"
 	"juansecalvinio/tepidolacuenta/internal/request/repository"
+	restaurantDomain "juansecalvinio/tepidolacuenta/internal/restaurant/domain"
 	restaurantRepo "juansecalvinio/tepidolacuenta/internal/restaurant/repository"
+	sessionDomain "juansecalvinio/tepidolacuenta/internal/session/domain"
+	sessionRepo "juansecalvinio/tepidolacuenta/internal/session/repository"
+	tableDomain "juansecalvinio/tepidolacuenta/internal/table/domain"
 	tableRepo "juansecalvinio/tepidolacuenta/internal/table/repository"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,38 +28,83 @@ import (
 type UseCase interface {
 	Create(ctx context.Context, input domain.CreateRequestInput) (*domain.Request, error)
 	GetVenueInfo(ctx context.Context, input domain.VenueInfoInput) (*domain.VenueInfo, error)
+	ResolveShortLink(ctx context.Context, input domain.ShortLinkInput) (*domain.ShortLinkResolution, error)
 	GetByID(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) (*domain.Request, error)
-	GetByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]*domain.Request, error)
-	GetPendingByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]*domain.Request, error)
+	GetByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, filter domain.RequestFilter, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]*domain.Request, error)
+	GetPendingByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, filter domain.PendingRequestFilter, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]*domain.Request, error)
 	UpdateStatus(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.UpdateRequestStatusInput, restaurantIDHint *primitive.ObjectID) (*domain.Request, error)
 	Delete(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) error
+	GetStats(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, filter domain.RequestStatsFilter, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.RequestStats, error)
+	GetScanReport(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, filter domain.ScanReportFilter) (*domain.ScanReport, error)
+	ArchiveExpired(ctx context.Context) (int64, error)
+	Transfer(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.TransferRequestInput, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.Request, error)
+	MergeTables(ctx context.Context, userID primitive.ObjectID, input domain.MergeTablesInput, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.MergeTablesResult, error)
+	UnmergeTables(ctx context.Context, targetTableID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]primitive.ObjectID, error)
+	GetFloorPlan(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.FloorPlan, error)
 }
 
+// NotifyFunc sends a message to the restaurant's connected WebSocket clients.
+// data is either a *domain.Request (new request) or one of the request events.
+// zoneIDs are the zones of the tables involved, so clients following a single
+// zone only get the events of their tables.
+type NotifyFunc func(restaurantID primitive.ObjectID, zoneIDs []primitive.ObjectID, data interface{})
+
+// archiveBatchSize is how many requests are moved to the archive per round trip
+const archiveBatchSize = 500
+
 type requestUseCase struct {
 	repo           repository.Repository
+	archiveRepo    repository.ArchiveRepository
+	scanRepo       repository.ScanRepository
 	restaurantRepo restaurantRepo.Repository
 	branchRepo     branchRepo.Repository
+	settingsRepo   branchRepo.SettingsRepository
 	tableRepo      tableRepo.Repository
+	shortLinkRepo  tableRepo.ShortLinkRepository
+	sessionRepo    sessionRepo.Repository
+	tx             database.Transactor
 	qrService      *pkg.QRService
-	notifyFunc     func(restaurantID primitive.ObjectID, request *domain.Request)
+	noteSanitizer  *pkg.NoteSanitizer
+	// tableLimiter and venueInfoLimiter cap public calls per table; tables over the limit get flagged
+	tableLimiter     *pkg.SlidingWindowLimiter
+	venueInfoLimiter *pkg.SlidingWindowLimiter
+	notifyFunc       NotifyFunc
 }
 
 // NewRequestUseCase creates a new request use case
 func NewRequestUseCase(
 	repo repository.Repository,
+	archiveRepo repository.ArchiveRepository,
+	scanRepo repository.ScanRepository,
 	restaurantRepo restaurantRepo.Repository,
 	branchRepo branchRepo.Repository,
+	settingsRepo branchRepo.SettingsRepository,
 	tableRepo tableRepo.Repository,
+	shortLinkRepo tableRepo.ShortLinkRepository,
+	sessionRepo sessionRepo.Repository,
+	tx database.Transactor,
 	qrService *pkg.QRService,
-	notifyFunc func(restaurantID primitive.ObjectID, request *domain.Request),
+	noteSanitizer *pkg.NoteSanitizer,
+	tableLimiter *pkg.SlidingWindowLimiter,
+	venueInfoLimiter *pkg.SlidingWindowLimiter,
+	notifyFunc NotifyFunc,
 ) UseCase {
 	return &requestUseCase{
-		repo:           repo,
-		restaurantRepo: restaurantRepo,
-		branchRepo:     branchRepo,
-		tableRepo:      tableRepo,
-		qrService:      qrService,
-		notifyFunc:     notifyFunc,
+		repo:             repo,
+		archiveRepo:      archiveRepo,
+		scanRepo:         scanRepo,
+		restaurantRepo:   restaurantRepo,
+		branchRepo:       branchRepo,
+		settingsRepo:     settingsRepo,
+		tableRepo:        tableRepo,
+		shortLinkRepo:    shortLinkRepo,
+		sessionRepo:      sessionRepo,
+		tx:               tx,
+		qrService:        qrService,
+		noteSanitizer:    noteSanitizer,
+		tableLimiter:     tableLimiter,
+		venueInfoLimiter: venueInfoLimiter,
+		notifyFunc:       notifyFunc,
 	}
 }
 
@@ -70,126 +125,413 @@ func authorizeRestaurantAccess(restaurantID, ownerUserID, callerUserID primitive
 
 // Create creates a new request from a QR code scan
 func (uc *requestUseCase) Create(ctx context.Context, input domain.CreateRequestInput) (*domain.Request, error) {
-	// Parse IDs
-	restaurantID, err := primitive.ObjectIDFromHex(input.RestaurantID)
+	restaurant, branch, table, err := uc.resolveScannedTable(ctx, input.TableQRParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if !branch.IsActive {
+		return nil, errors.New("branch is not active")
+	}
+
+	now := time.Now()
+	if !branch.IsOpenAt(now) {
+		if next := branch.NextOpening(now); next != nil {
+			return nil, fmt.Errorf("%w, it opens again on %s", pkg.ErrBranchClosed, next.Format("02/01 at 15:04"))
+		}
+		return nil, pkg.ErrBranchClosed
+	}
+
+	if !table.IsActive {
+		return nil, errors.New("table is not active")
+	}
+
+	if err := checkGeofence(branch, table, input); err != nil {
+		return nil, err
+	}
+
+	if table.IsPublicBlocked(now) {
+		return nil, pkg.ErrTableBlocked
+	}
+
+	settings, err := uc.branchSettings(ctx, branch.ID)
+	if err != nil {
+		return nil, err
+	}
+	requestType, paymentMethod, err := acceptedRequest(settings, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// While the table is merged into another one, the request belongs to the target table
+	target := uc.resolveMergedTable(ctx, table)
+
+	// Check for an existing pending request of the same type on the table. It goes before
+	// the rate limit, so diners retrying while their request is pending don't use up the budget.
+	exists, err := uc.repo.ExistsPendingForTable(ctx, target.ID, requestType)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, pkg.ErrRequestAlreadyPending
+	}
+
+	if !uc.tableLimiter.Allow(table.ID.Hex()) {
+		uc.flagTable(ctx, restaurant.ID, table, tableDomain.FlagReasonRequestRateLimit)
+		return nil, pkg.ErrTooManyRequests
+	}
+	table = target
+
+	// Clean up the optional diner note before storing or broadcasting it
+	note := uc.noteSanitizer.Sanitize(input.Note)
+
+	// Create request
+	request := domain.NewRequest(restaurant.ID, branch.ID, tableRef(table), requestType, paymentMethod, note)
+	request.SessionID = uc.openSessionID(ctx, table.ID)
+
+	if err := uc.repo.Create(ctx, request); err != nil {
+		return nil, err
+	}
+
+	// Notify restaurant via WebSocket
+	if uc.notifyFunc != nil {
+		uc.notifyFunc(restaurant.ID, zoneIDs(request.ZoneID), request)
+	}
+
+	return request, nil
+}
+
+// checkGeofence verifies the diner sent a location within the branch geofence.
+// In log-only mode requests that fail the check are logged and let through.
+func checkGeofence(branch *branchDomain.Branch, table *tableDomain.Table, input domain.CreateRequestInput) error {
+	fence := branch.Geofence
+	if fence == nil {
+		return nil
+	}
+
+	if input.Latitude == nil || input.Longitude == nil {
+		if fence.IsStrict() {
+			return fmt.Errorf("%w, please share your location", pkg.ErrOutsideGeofence)
+		}
+		log.Printf("Geofence: request for table %s of branch %s sent without a location", table.ID.Hex(), branch.ID.Hex())
+		return nil
+	}
+
+	if fence.Contains(*input.Latitude, *input.Longitude) {
+		return nil
+	}
+	if fence.IsStrict() {
+		return pkg.ErrOutsideGeofence
+	}
+	log.Printf("Geofence: request for table %s of branch %s sent %.0fm away, outside the %dm radius",
+		table.ID.Hex(), branch.ID.Hex(), fence.DistanceMeters(*input.Latitude, *input.Longitude), fence.RadiusMeters)
+	return nil
+}
+
+// acceptedRequest checks the request type and payment method against the branch
+// settings. The type defaults to a bill request, the only one with a payment method.
+func acceptedRequest(settings *branchDomain.Settings, input domain.CreateRequestInput) (domain.RequestType, domain.PaymentMethod, error) {
+	requestType := domain.TypeBill
+	if input.Type != "" {
+		requestType = domain.RequestType(input.Type)
+	}
+	if !settings.AcceptsRequestType(string(requestType)) {
+		return "", "", fmt.Errorf("%w: this venue doesn't take %s requests", pkg.ErrInvalidInput, requestType)
+	}
+
+	if requestType != domain.TypeBill {
+		return requestType, "", nil
+	}
+	if input.PaymentMethod == "" {
+		return "", "", fmt.Errorf("%w: a payment method is required to ask for the bill", pkg.ErrInvalidInput)
+	}
+	if !settings.AcceptsPaymentMethod(input.PaymentMethod) {
+		return "", "", fmt.Errorf("%w: this venue doesn't accept %s", pkg.ErrInvalidInput, input.PaymentMethod)
+	}
+	return requestType, domain.PaymentMethod(input.PaymentMethod), nil
+}
+
+// branchSettings returns the branch's settings, or the defaults if it has none
+func (uc *requestUseCase) branchSettings(ctx context.Context, branchID primitive.ObjectID) (*branchDomain.Settings, error) {
+	settings, err := uc.settingsRepo.FindByBranchID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return branchDomain.DefaultSettings(branchID), nil
+	}
+	return settings, nil
+}
+
+// flagTable flags a table that went over a public rate limit and lets the dashboard know.
+// Only the first flag is kept until the owner clears it. Failures are logged, not returned,
+// since the caller is already rejecting the request.
+func (uc *requestUseCase) flagTable(ctx context.Context, restaurantID primitive.ObjectID, table *tableDomain.Table, reason string) {
+	now := time.Now()
+	flagged, err := uc.tableRepo.Flag(ctx, table.ID, reason, now)
+	if err != nil {
+		log.Printf("Failed to flag table %s: %v", table.ID.Hex(), err)
+		return
+	}
+
+	if flagged && uc.notifyFunc != nil {
+		uc.notifyFunc(restaurantID, zoneIDs(table.ZoneID), domain.NewTableFlaggedEvent(table.BranchID, tableRef(table), reason, now))
+	}
+}
+
+// openSessionID returns the ID of the table's open session, or nil if no party is seated.
+// Lookup failures are logged and leave the request unlinked instead of failing it.
+func (uc *requestUseCase) openSessionID(ctx context.Context, tableID primitive.ObjectID) *primitive.ObjectID {
+	session, err := uc.sessionRepo.FindOpenByTableID(ctx, tableID)
+	if err != nil {
+		log.Printf("Failed to look up the open session of table %s: %v", tableID.Hex(), err)
+		return nil
+	}
+	if session == nil {
+		return nil
+	}
+	return &session.ID
+}
+
+// closeBillSessions closes the session a request was made in after its bill was
+// attended, along with the open sessions of the tables merged into its table.
+// Failures are logged, not returned, since the request itself was already updated.
+func (uc *requestUseCase) closeBillSessions(ctx context.Context, request *domain.Request) {
+	sessions := make([]*sessionDomain.TableSession, 0)
+	if request.SessionID != nil {
+		session, err := uc.sessionRepo.FindByID(ctx, *request.SessionID)
+		if err != nil {
+			log.Printf("Failed to load session %s: %v", request.SessionID.Hex(), err)
+		} else {
+			sessions = append(sessions, session)
+		}
+	}
+
+	merged, err := uc.tableRepo.FindMergedInto(ctx, request.TableID)
+	if err != nil {
+		log.Printf("Failed to load the tables merged into %s: %v", request.TableID.Hex(), err)
+	}
+	for _, table := range merged {
+		session, err := uc.sessionRepo.FindOpenByTableID(ctx, table.ID)
+		if err != nil {
+			log.Printf("Failed to look up the open session of table %s: %v", table.ID.Hex(), err)
+			continue
+		}
+		if session != nil {
+			sessions = append(sessions, session)
+		}
+	}
+
+	for _, session := range sessions {
+		if session.Status != sessionDomain.StatusOpen {
+			continue
+		}
+		session.Close(sessionDomain.CloseReasonBillAttended, nil)
+		if err := uc.sessionRepo.Close(ctx, session); err != nil && !errors.Is(err, pkg.ErrSessionClosed) {
+			log.Printf("Failed to close session %s: %v", session.ID.Hex(), err)
+		}
+	}
+}
+
+// resolveScannedTable validates the params of a scanned QR code and loads the
+// restaurant, branch and table it points to. Token codes are resolved through
+// the table token, so the current table number is used even if the table was
+// renumbered after the code was printed. Codes issued before the table's last
+// QR rotation return pkg.ErrQRCodeRevoked.
+func (uc *requestUseCase) resolveScannedTable(ctx context.Context, params domain.TableQRParams) (*restaurantDomain.Restaurant, *branchDomain.Branch, *tableDomain.Table, error) {
+	if params.TableToken != "" {
+		table, err := uc.tableRepo.FindByToken(ctx, params.TableToken)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if err := uc.qrService.ValidateTableQRCode(params.TableToken, params.QRVersion, params.Hash, table.QRVersion); err != nil {
+			return nil, nil, nil, err
+		}
+
+		branch, err := uc.branchRepo.FindByID(ctx, table.BranchID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		restaurant, err := uc.restaurantRepo.FindByID(ctx, branch.RestaurantID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		return restaurant, branch, table, nil
+	}
+
+	// Legacy codes carry the IDs and the table number they were printed with
+	restaurantID, err := primitive.ObjectIDFromHex(params.RestaurantID)
 	if err != nil {
-		return nil, errors.New("invalid restaurant ID")
+		return nil, nil, nil, errors.New("invalid restaurant ID")
 	}
 
-	branchID, err := primitive.ObjectIDFromHex(input.BranchID)
+	branchID, err := primitive.ObjectIDFromHex(params.BranchID)
 	if err != nil {
-		return nil, errors.New("invalid branch ID")
+		return nil, nil, nil, errors.New("invalid branch ID")
 	}
 
-	tableID, err := primitive.ObjectIDFromHex(input.TableID)
+	tableID, err := primitive.ObjectIDFromHex(params.TableID)
 	if err != nil {
-		return nil, errors.New("invalid table ID")
+		return nil, nil, nil, errors.New("invalid table ID")
 	}
 
 	// Validate QR code
-	if !uc.qrService.ValidateTableQRCode(restaurantID, branchID, tableID, input.TableNumber, input.Hash) {
-		return nil, errors.New("invalid QR code")
+	if err := uc.qrService.ValidateLegacyTableQRCode(restaurantID, branchID, tableID, params.TableNumber, params.Hash); err != nil {
+		return nil, nil, nil, err
 	}
 
 	// Verify restaurant exists
 	restaurant, err := uc.restaurantRepo.FindByID(ctx, restaurantID)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	// Verify branch exists and belongs to restaurant
 	branch, err := uc.branchRepo.FindByID(ctx, branchID)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	if branch.RestaurantID != restaurantID {
-		return nil, errors.New("branch does not belong to restaurant")
-	}
-
-	if !branch.IsActive {
-		return nil, errors.New("branch is not active")
+		return nil, nil, nil, errors.New("branch does not belong to restaurant")
 	}
 
 	// Verify table exists and belongs to branch
 	table, err := uc.tableRepo.FindByID(ctx, tableID)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	if table.BranchID != branchID {
-		return nil, errors.New("table does not belong to branch")
+		return nil, nil, nil, errors.New("table does not belong to branch")
 	}
 
-	if !table.IsActive {
-		return nil, errors.New("table is not active")
+	// Legacy stickers predate QR versions, so any rotation revokes them
+	if table.QRVersion > 0 {
+		return nil, nil, nil, pkg.ErrQRCodeRevoked
+	}
+
+	return restaurant, branch, table, nil
+}
+
+// resolveMergedTable returns the table a merged table currently points to.
+// If the target is gone or no longer in the same branch the merge is ignored.
+func (uc *requestUseCase) resolveMergedTable(ctx context.Context, table *tableDomain.Table) *tableDomain.Table {
+	if table.MergedIntoTableID == nil {
+		return table
+	}
+
+	target, err := uc.tableRepo.FindByID(ctx, *table.MergedIntoTableID)
+	if err != nil || target.BranchID != table.BranchID || !target.IsActive {
+		return table
 	}
+	return target
+}
 
-	// Check for existing pending request on the same table
-	exists, err := uc.repo.ExistsPendingForTable(ctx, tableID)
+// GetVenueInfo returns the public restaurant/branch/table info for a scanned QR.
+// Validates the QR hash so venue names can't be enumerated by guessing IDs.
+func (uc *requestUseCase) GetVenueInfo(ctx context.Context, input domain.VenueInfoInput) (*domain.VenueInfo, error) {
+	restaurant, branch, table, err := uc.resolveScannedTable(ctx, input.TableQRParams)
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, pkg.ErrRequestAlreadyPending
+
+	if !uc.venueInfoLimiter.Allow(table.ID.Hex()) {
+		uc.flagTable(ctx, restaurant.ID, table, tableDomain.FlagReasonVenueInfoRateLimit)
+		return nil, pkg.ErrTooManyRequests
 	}
 
-	// Create request
-	request := domain.NewRequest(restaurantID, branchID, tableID, input.TableNumber, domain.PaymentMethod(input.PaymentMethod))
+	// Every venue info lookup is a scan; losing one isn't worth failing the diner's page
+	event := domain.ScanEvent{At: time.Now(), UserAgentClass: domain.ClassifyUserAgent(input.UserAgent)}
+	if err := uc.scanRepo.Record(ctx, restaurant.ID, branch.ID, table.ID, event); err != nil {
+		log.Printf("Failed to record scan of table %s: %v", table.ID.Hex(), err)
+	}
 
-	if err := uc.repo.Create(ctx, request); err != nil {
+	settings, err := uc.branchSettings(ctx, branch.ID)
+	if err != nil {
 		return nil, err
 	}
 
-	// Notify restaurant via WebSocket
-	if uc.notifyFunc != nil {
-		uc.notifyFunc(restaurant.ID, request)
-	}
+	return newVenueInfo(restaurant, branch, settings, table, event.At), nil
+}
 
-	return request, nil
+// newVenueInfo builds the public venue info of a table, including whether its
+// branch is open at now and the options its settings offer
+func newVenueInfo(restaurant *restaurantDomain.Restaurant, branch *branchDomain.Branch, settings *branchDomain.Settings, table *tableDomain.Table, now time.Time) *domain.VenueInfo {
+	return &domain.VenueInfo{
+		RestaurantName: restaurant.Name,
+		BranchAddress:  branch.Address,
+		TableNumber:    table.Number,
+		TableLabel:     table.Label,
+		TableCapacity:  table.Capacity,
+		IsOpen:         branch.IsOpenAt(now),
+		NextOpening:    branch.NextOpening(now),
+		PaymentMethods: settings.PaymentMethods,
+		RequestTypes:   settings.RequestTypes,
+		// Log-only geofences need the location too, or there is nothing to log
+		LocationRequired: branch.Geofence != nil,
+	}
 }
 
-// GetVenueInfo returns the public restaurant/branch/table info for a scanned QR.
-// Validates the QR hash so venue names can't be enumerated by guessing IDs.
-func (uc *requestUseCase) GetVenueInfo(ctx context.Context, input domain.VenueInfoInput) (*domain.VenueInfo, error) {
-	restaurantID, err := primitive.ObjectIDFromHex(input.RestaurantID)
+// ResolveShortLink resolves a scanned QR short link to the table's request page
+// and venue info, and counts the scan. Links issued before the table's last QR
+// rotation return pkg.ErrQRCodeRevoked.
+func (uc *requestUseCase) ResolveShortLink(ctx context.Context, input domain.ShortLinkInput) (*domain.ShortLinkResolution, error) {
+	link, err := uc.shortLinkRepo.FindBySlug(ctx, input.Slug)
 	if err != nil {
-		return nil, errors.New("invalid restaurant ID")
+		return nil, err
 	}
 
-	branchID, err := primitive.ObjectIDFromHex(input.BranchID)
-	if err != nil {
-		return nil, errors.New("invalid branch ID")
+	// Scans of revoked links are counted too, so abused stickers show up
+	if err := uc.shortLinkRepo.RecordScan(ctx, link.ID, time.Now()); err != nil {
+		log.Printf("Failed to record scan of short link %s: %v", link.Slug, err)
 	}
 
-	tableID, err := primitive.ObjectIDFromHex(input.TableID)
+	table, err := uc.tableRepo.FindByToken(ctx, link.TableToken)
 	if err != nil {
-		return nil, errors.New("invalid table ID")
+		return nil, err
 	}
 
-	// Validate QR code
-	if !uc.qrService.ValidateTableQRCode(restaurantID, branchID, tableID, input.TableNumber, input.Hash) {
-		return nil, errors.New("invalid QR code")
+	if link.QRVersion < table.QRVersion {
+		return nil, pkg.ErrQRCodeRevoked
 	}
 
-	restaurant, err := uc.restaurantRepo.FindByID(ctx, restaurantID)
+	branch, err := uc.branchRepo.FindByID(ctx, table.BranchID)
 	if err != nil {
 		return nil, err
 	}
 
-	branch, err := uc.branchRepo.FindByID(ctx, branchID)
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, branch.RestaurantID)
 	if err != nil {
 		return nil, err
 	}
 
-	if branch.RestaurantID != restaurantID {
-		return nil, errors.New("branch does not belong to restaurant")
+	if !uc.venueInfoLimiter.Allow(table.ID.Hex()) {
+		uc.flagTable(ctx, restaurant.ID, table, tableDomain.FlagReasonVenueInfoRateLimit)
+		return nil, pkg.ErrTooManyRequests
 	}
 
-	return &domain.VenueInfo{
-		RestaurantName: restaurant.Name,
-		BranchAddress:  branch.Address,
-		TableNumber:    input.TableNumber,
+	// Redirected browsers are counted when the request page loads the venue info;
+	// clients getting it here won't load it again
+	now := time.Now()
+	if input.VenueInfoOnly {
+		event := domain.ScanEvent{At: now, UserAgentClass: domain.ClassifyUserAgent(input.UserAgent)}
+		if err := uc.scanRepo.Record(ctx, restaurant.ID, branch.ID, table.ID, event); err != nil {
+			log.Printf("Failed to record scan of table %s: %v", table.ID.Hex(), err)
+		}
+	}
+
+	settings, err := uc.branchSettings(ctx, branch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ShortLinkResolution{
+		RequestURL: table.QRCode,
+		VenueInfo:  newVenueInfo(restaurant, branch, settings, table, now),
 	}, nil
 }
 
@@ -212,9 +554,9 @@ func (uc *requestUseCase) GetByID(ctx context.Context, id primitive.ObjectID, us
 	return request, nil
 }
 
-// GetByRestaurantID retrieves all requests for a restaurant.
+// GetByRestaurantID retrieves all requests for a restaurant matching the given filter.
 // Branch-scoped employees only get the requests of their branch.
-func (uc *requestUseCase) GetByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]*domain.Request, error) {
+func (uc *requestUseCase) GetByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, filter domain.RequestFilter, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]*domain.Request, error) {
 	restaurant, err := uc.restaurantRepo.FindByID(ctx, restaurantID)
 	if err != nil {
 		return nil, err
@@ -225,14 +567,14 @@ func (uc *requestUseCase) GetByRestaurantID(ctx context.Context, restaurantID pr
 	}
 
 	if branchIDHint != nil {
-		return uc.repo.FindByBranchID(ctx, *branchIDHint)
+		return uc.repo.FindByBranchID(ctx, *branchIDHint, filter)
 	}
-	return uc.repo.FindByRestaurantID(ctx, restaurantID)
+	return uc.repo.FindByRestaurantID(ctx, restaurantID, filter)
 }
 
-// GetPendingByRestaurantID retrieves all pending requests for a restaurant.
+// GetPendingByRestaurantID retrieves all pending requests for a restaurant matching the given filter.
 // Branch-scoped employees only get the pending requests of their branch.
-func (uc *requestUseCase) GetPendingByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]*domain.Request, error) {
+func (uc *requestUseCase) GetPendingByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, filter domain.PendingRequestFilter, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]*domain.Request, error) {
 	restaurant, err := uc.restaurantRepo.FindByID(ctx, restaurantID)
 	if err != nil {
 		return nil, err
@@ -243,9 +585,9 @@ func (uc *requestUseCase) GetPendingByRestaurantID(ctx context.Context, restaura
 	}
 
 	if branchIDHint != nil {
-		return uc.repo.FindPendingByBranchID(ctx, *branchIDHint)
+		return uc.repo.FindPendingByBranchID(ctx, *branchIDHint, filter)
 	}
-	return uc.repo.FindPendingByRestaurantID(ctx, restaurantID)
+	return uc.repo.FindPendingByRestaurantID(ctx, restaurantID, filter)
 }
 
 // UpdateStatus updates a request's status
@@ -264,12 +606,18 @@ func (uc *requestUseCase) UpdateStatus(ctx context.Context, id primitive.ObjectI
 		return nil, err
 	}
 
+	billAttended := request.Type == domain.TypeBill && request.Status != domain.StatusAttended && input.Status == string(domain.StatusAttended)
 	request.UpdateStatus(domain.RequestStatus(input.Status))
 
 	if err := uc.repo.Update(ctx, request); err != nil {
 		return nil, err
 	}
 
+	// Once the bill is brought the party is leaving, so their table is free again
+	if billAttended {
+		uc.closeBillSessions(ctx, request)
+	}
+
 	return request, nil
 }
 
@@ -291,3 +639,428 @@ func (uc *requestUseCase) Delete(ctx context.Context, id primitive.ObjectID, use
 
 	return uc.repo.Delete(ctx, id)
 }
+
+// GetStats returns aggregate request counts for a restaurant, including requests
+// already moved to the archive. Branch-scoped employees only get their branch's counts.
+func (uc *requestUseCase) GetStats(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, filter domain.RequestStatsFilter, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.RequestStats, error) {
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRestaurantAccess(restaurant.ID, restaurant.UserID, userID, restaurantIDHint); err != nil {
+		return nil, err
+	}
+
+	// The "to" date is inclusive for callers, so count up to the start of the next day
+	to := filter.To
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	hot, err := uc.repo.CountGrouped(ctx, restaurantID, branchIDHint, filter.From, to)
+	if err != nil {
+		return nil, err
+	}
+
+	archived, err := uc.archiveRepo.CountGrouped(ctx, restaurantID, branchIDHint, filter.From, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.RequestStats{
+		ByStatus:        make(map[domain.RequestStatus]int64),
+		ByPaymentMethod: make(map[domain.PaymentMethod]int64),
+	}
+	for _, counts := range [][]domain.RequestCount{hot, archived} {
+		for _, c := range counts {
+			stats.Total += c.Count
+			stats.ByStatus[c.Status] += c.Count
+			stats.ByPaymentMethod[c.PaymentMethod] += c.Count
+		}
+	}
+	for _, c := range archived {
+		stats.Archived += c.Count
+	}
+
+	return stats, nil
+}
+
+// ArchiveExpired moves the requests older than each restaurant's retention period
+// to the archive collection, stripping diner notes, and deletes them from the hot
+// collection. Returns the number of requests archived.
+func (uc *requestUseCase) ArchiveExpired(ctx context.Context) (int64, error) {
+	restaurants, err := uc.restaurantRepo.FindWithRequestRetention(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, restaurant := range restaurants {
+		cutoff := time.Now().AddDate(0, 0, -restaurant.RequestRetentionDays)
+
+		archived, err := uc.archiveRestaurantRequests(ctx, restaurant.ID, cutoff)
+		total += archived
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// archiveRestaurantRequests archives a restaurant's requests created before cutoff in batches.
+// Each batch is written to the archive before being deleted, so a failure never loses data.
+// When ctx is cancelled the batch in progress is completed and no new one is started.
+func (uc *requestUseCase) archiveRestaurantRequests(ctx context.Context, restaurantID primitive.ObjectID, cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		batchCtx := context.WithoutCancel(ctx)
+
+		requests, err := uc.repo.FindCreatedBefore(batchCtx, restaurantID, cutoff, archiveBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(requests) == 0 {
+			return total, nil
+		}
+
+		archived := make([]*domain.ArchivedRequest, 0, len(requests))
+		ids := make([]primitive.ObjectID, 0, len(requests))
+		for _, request := range requests {
+			archived = append(archived, domain.NewArchivedRequest(request))
+			ids = append(ids, request.ID)
+		}
+
+		if err := uc.archiveRepo.InsertMany(batchCtx, archived); err != nil {
+			return total, err
+		}
+
+		deleted, err := uc.repo.DeleteByIDs(batchCtx, ids)
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+
+		if len(requests) < archiveBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// tableRef returns the details of a table that are copied onto its requests and events
+func tableRef(table *tableDomain.Table) domain.TableRef {
+	return domain.TableRef{
+		ID:       table.ID,
+		Number:   table.Number,
+		Label:    table.Label,
+		Capacity: table.Capacity,
+		ZoneID:   table.ZoneID,
+		Priority: table.Priority,
+	}
+}
+
+// zoneIDs returns the distinct zones of the tables an event is about, skipping tables without a zone
+func zoneIDs(tableZoneIDs ...*primitive.ObjectID) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(tableZoneIDs))
+	for _, id := range tableZoneIDs {
+		if id != nil && !slices.Contains(ids, *id) {
+			ids = append(ids, *id)
+		}
+	}
+	return ids
+}
+
+// authorizeBranchScope checks that a branch-scoped employee only touches their own branch
+func authorizeBranchScope(branchID primitive.ObjectID, branchIDHint *primitive.ObjectID) error {
+	if branchIDHint != nil && branchID != *branchIDHint {
+		return pkg.ErrForbidden
+	}
+	return nil
+}
+
+// findBranchTable loads a table and verifies it belongs to the given branch
+func (uc *requestUseCase) findBranchTable(ctx context.Context, tableIDStr string, branchID primitive.ObjectID) (*tableDomain.Table, error) {
+	tableID, err := primitive.ObjectIDFromHex(tableIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid table ID", pkg.ErrInvalidInput)
+	}
+
+	table, err := uc.tableRepo.FindByID(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	if table.BranchID != branchID {
+		return nil, fmt.Errorf("%w: table %d belongs to another branch", pkg.ErrInvalidInput, table.Number)
+	}
+
+	return table, nil
+}
+
+// Transfer moves a pending request to another table of the same branch,
+// e.g. when the party changes tables before paying.
+func (uc *requestUseCase) Transfer(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.TransferRequestInput, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.Request, error) {
+	request, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, request.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRestaurantAccess(restaurant.ID, restaurant.UserID, userID, restaurantIDHint); err != nil {
+		return nil, err
+	}
+
+	if err := authorizeBranchScope(request.BranchID, branchIDHint); err != nil {
+		return nil, err
+	}
+
+	if request.Status != domain.StatusPending {
+		return nil, pkg.ErrRequestNotPending
+	}
+
+	target, err := uc.findBranchTable(ctx, input.TableID, request.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.ID == request.TableID {
+		return request, nil
+	}
+
+	if !target.IsActive {
+		return nil, fmt.Errorf("%w: table %d is not active", pkg.ErrInvalidInput, target.Number)
+	}
+
+	// A table can only hold one pending request of each type
+	exists, err := uc.repo.ExistsPendingForTable(ctx, target.ID, request.Type)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, pkg.ErrRequestAlreadyPending
+	}
+
+	from := domain.TableRef{ID: request.TableID, Number: request.TableNumber, Label: request.TableLabel, ZoneID: request.ZoneID}
+	request.MoveToTable(tableRef(target))
+	request.SessionID = uc.openSessionID(ctx, target.ID)
+
+	if err := uc.repo.Update(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if uc.notifyFunc != nil {
+		uc.notifyFunc(restaurant.ID, zoneIDs(from.ZoneID, request.ZoneID), domain.NewRequestTransferredEvent(request, from))
+	}
+
+	return request, nil
+}
+
+// MergeTables joins the source tables into the target table for a seating.
+// Pending requests on the source tables move to the target; if the target already
+// has one of the same type (or gets one from an earlier source), the extra requests
+// are cancelled since the merged party gets a single bill and a single waiter call.
+// Until unmerged, scans of a source table create requests on the target.
+func (uc *requestUseCase) MergeTables(ctx context.Context, userID primitive.ObjectID, input domain.MergeTablesInput, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.MergeTablesResult, error) {
+	targetID, err := primitive.ObjectIDFromHex(input.TargetTableID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid table ID", pkg.ErrInvalidInput)
+	}
+
+	target, err := uc.tableRepo.FindByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	branch, err := uc.branchRepo.FindByID(ctx, target.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, branch.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRestaurantAccess(restaurant.ID, restaurant.UserID, userID, restaurantIDHint); err != nil {
+		return nil, err
+	}
+
+	if err := authorizeBranchScope(branch.ID, branchIDHint); err != nil {
+		return nil, err
+	}
+
+	if !target.IsActive {
+		return nil, fmt.Errorf("%w: table %d is not active", pkg.ErrInvalidInput, target.Number)
+	}
+
+	if target.MergedIntoTableID != nil {
+		return nil, fmt.Errorf("%w: table %d is already merged into another table", pkg.ErrInvalidInput, target.Number)
+	}
+
+	// Validate every source before touching anything
+	sources := make([]*tableDomain.Table, 0, len(input.SourceTableIDs))
+	seen := make(map[primitive.ObjectID]bool)
+	for _, idStr := range input.SourceTableIDs {
+		source, err := uc.findBranchTable(ctx, idStr, branch.ID)
+		if err != nil {
+			return nil, err
+		}
+		if source.ID == target.ID {
+			return nil, fmt.Errorf("%w: a table can't be merged into itself", pkg.ErrInvalidInput)
+		}
+		if seen[source.ID] {
+			continue
+		}
+		if source.MergedIntoTableID != nil && *source.MergedIntoTableID != target.ID {
+			return nil, fmt.Errorf("%w: table %d is already merged into another table", pkg.ErrInvalidInput, source.Number)
+		}
+		// Scans only follow one merge hop, so a table other tables are merged into can't be merged away
+		children, err := uc.tableRepo.FindMergedInto(ctx, source.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(children) > 0 {
+			return nil, fmt.Errorf("%w: other tables are merged into table %d, unmerge them first", pkg.ErrInvalidInput, source.Number)
+		}
+		seen[source.ID] = true
+		sources = append(sources, source)
+	}
+
+	var result *domain.MergeTablesResult
+	var transferredFrom []*tableDomain.Table
+
+	// Tables and requests change together, so a failure halfway leaves nothing merged
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		result = &domain.MergeTablesResult{
+			TargetTableID:  target.ID,
+			SourceTableIDs: make([]primitive.ObjectID, 0, len(sources)),
+			Transferred:    make([]*domain.Request, 0),
+			Cancelled:      make([]*domain.Request, 0),
+		}
+		transferredFrom = make([]*tableDomain.Table, 0)
+
+		targetPending, err := uc.repo.FindPendingByTableID(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+		targetHasPending := make(map[domain.RequestType]bool, len(targetPending))
+		for _, pending := range targetPending {
+			targetHasPending[pending.Type] = true
+		}
+
+		for _, source := range sources {
+			source.MergedIntoTableID = &target.ID
+			if err := uc.tableRepo.Update(ctx, source); err != nil {
+				return err
+			}
+			result.SourceTableIDs = append(result.SourceTableIDs, source.ID)
+
+			sourcePending, err := uc.repo.FindPendingByTableID(ctx, source.ID)
+			if err != nil {
+				return err
+			}
+
+			for _, pending := range sourcePending {
+				if targetHasPending[pending.Type] {
+					pending.UpdateStatus(domain.StatusCancelled)
+					if err := uc.repo.Update(ctx, pending); err != nil {
+						return err
+					}
+					result.Cancelled = append(result.Cancelled, pending)
+					continue
+				}
+
+				pending.MoveToTable(tableRef(target))
+				pending.SessionID = uc.openSessionID(ctx, target.ID)
+				if err := uc.repo.Update(ctx, pending); err != nil {
+					return err
+				}
+				targetHasPending[pending.Type] = true
+				result.Transferred = append(result.Transferred, pending)
+				transferredFrom = append(transferredFrom, source)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Notify only once the merge is committed
+	if uc.notifyFunc != nil {
+		for i, pending := range result.Transferred {
+			source := transferredFrom[i]
+			uc.notifyFunc(restaurant.ID, zoneIDs(source.ZoneID, target.ZoneID), domain.NewRequestTransferredEvent(pending, tableRef(source)))
+		}
+
+		tableZoneIDs := []*primitive.ObjectID{target.ZoneID}
+		for _, source := range sources {
+			tableZoneIDs = append(tableZoneIDs, source.ZoneID)
+		}
+		uc.notifyFunc(restaurant.ID, zoneIDs(tableZoneIDs...), domain.NewTablesMergeEvent(domain.EventTablesMerged, branch.ID, target.ID, result.SourceTableIDs))
+	}
+
+	return result, nil
+}
+
+// UnmergeTables releases every table merged into the target table.
+// Returns the IDs of the released tables.
+func (uc *requestUseCase) UnmergeTables(ctx context.Context, targetTableID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]primitive.ObjectID, error) {
+	target, err := uc.tableRepo.FindByID(ctx, targetTableID)
+	if err != nil {
+		return nil, err
+	}
+
+	branch, err := uc.branchRepo.FindByID(ctx, target.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, branch.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRestaurantAccess(restaurant.ID, restaurant.UserID, userID, restaurantIDHint); err != nil {
+		return nil, err
+	}
+
+	if err := authorizeBranchScope(branch.ID, branchIDHint); err != nil {
+		return nil, err
+	}
+
+	// Only the merge field is cleared, so concurrent edits to the source tables are kept,
+	// and the tables that get released are the ones read in the same transaction
+	var sources []*tableDomain.Table
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		sources, err = uc.tableRepo.FindMergedInto(ctx, target.ID)
+		if err != nil || len(sources) == 0 {
+			return err
+		}
+		return uc.tableRepo.ClearMergedInto(ctx, target.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	released := make([]primitive.ObjectID, 0, len(sources))
+	tableZoneIDs := []*primitive.ObjectID{target.ZoneID}
+	for _, source := range sources {
+		released = append(released, source.ID)
+		tableZoneIDs = append(tableZoneIDs, source.ZoneID)
+	}
+
+	if len(released) > 0 && uc.notifyFunc != nil {
+		uc.notifyFunc(restaurant.ID, zoneIDs(tableZoneIDs...), domain.NewTablesMergeEvent(domain.EventTablesUnmerged, branch.ID, target.ID, released))
+	}
+
+	return released, nil
+}
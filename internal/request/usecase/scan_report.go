@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/request/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Scan report limits
+const (
+	defaultScanReportDays = 30
+	maxScanReportDays     = 92
+	busiestTablesPerDay   = 5
+)
+
+const dateLayout = "2006-01-02"
+
+// GetScanReport reports QR scans, how many turned into bill requests and the busiest
+// tables of each day (UTC) for a restaurant, optionally limited to one branch.
+// Defaults to the last 30 days.
+func (uc *requestUseCase) GetScanReport(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID, filter domain.ScanReportFilter) (*domain.ScanReport, error) {
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRestaurantAccess(restaurant.ID, restaurant.UserID, userID, nil); err != nil {
+		return nil, err
+	}
+
+	var branchID *primitive.ObjectID
+	if filter.BranchID != "" {
+		id, err := primitive.ObjectIDFromHex(filter.BranchID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid branch ID", pkg.ErrInvalidInput)
+		}
+		branch, err := uc.branchRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if branch.RestaurantID != restaurant.ID {
+			return nil, fmt.Errorf("%w: branch does not belong to restaurant", pkg.ErrInvalidInput)
+		}
+		branchID = &id
+	}
+
+	to := filter.To
+	if to.IsZero() {
+		to = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	from := filter.From
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -(defaultScanReportDays - 1))
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: from must not be after to", pkg.ErrInvalidInput)
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > maxScanReportDays {
+		return nil, fmt.Errorf("%w: the date range can't exceed %d days", pkg.ErrInvalidInput, maxScanReportDays)
+	}
+
+	// The "to" date is inclusive for callers, so count up to the start of the next day
+	end := to.AddDate(0, 0, 1)
+
+	scans, err := uc.scanRepo.CountByTableAndDay(ctx, restaurant.ID, branchID, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only bill requests count as conversions; a waiter call can follow the same scan
+	hot, err := uc.repo.CountByTableAndDay(ctx, restaurant.ID, branchID, domain.TypeBill, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	archived, err := uc.archiveRepo.CountByTableAndDay(ctx, restaurant.ID, branchID, domain.TypeBill, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ScanReport{
+		From:        from.Format(dateLayout),
+		To:          to.Format(dateLayout),
+		ByUserAgent: make(map[string]int64),
+		Days:        make([]domain.ScanDay, 0, days),
+	}
+
+	// Per day, per table stats
+	tablesByDay := make(map[string]map[primitive.ObjectID]*domain.TableScanStats)
+	tableStats := func(date string, tableID primitive.ObjectID) *domain.TableScanStats {
+		tables, ok := tablesByDay[date]
+		if !ok {
+			tables = make(map[primitive.ObjectID]*domain.TableScanStats)
+			tablesByDay[date] = tables
+		}
+		stats, ok := tables[tableID]
+		if !ok {
+			stats = &domain.TableScanStats{TableID: tableID}
+			tables[tableID] = stats
+		}
+		return stats
+	}
+
+	for _, c := range scans {
+		tableStats(c.Date, c.TableID).Scans += c.Count
+		report.Scans += c.Count
+		report.ByUserAgent[c.UserAgentClass] += c.Count
+	}
+	for _, counts := range [][]domain.TableDayCount{hot, archived} {
+		for _, c := range counts {
+			tableStats(c.Date, c.TableID).Requests += c.Count
+			report.Requests += c.Count
+		}
+	}
+	report.ConversionRate = domain.ConversionRate(report.Scans, report.Requests)
+
+	tableNumbers := make(map[primitive.ObjectID]int)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		scanDay := domain.ScanDay{Date: date, BusiestTables: make([]domain.TableScanStats, 0)}
+
+		tables := make([]*domain.TableScanStats, 0, len(tablesByDay[date]))
+		for _, stats := range tablesByDay[date] {
+			scanDay.Scans += stats.Scans
+			scanDay.Requests += stats.Requests
+			tables = append(tables, stats)
+		}
+		scanDay.ConversionRate = domain.ConversionRate(scanDay.Scans, scanDay.Requests)
+
+		sort.Slice(tables, func(i, j int) bool {
+			if tables[i].Scans != tables[j].Scans {
+				return tables[i].Scans > tables[j].Scans
+			}
+			if tables[i].Requests != tables[j].Requests {
+				return tables[i].Requests > tables[j].Requests
+			}
+			return tables[i].TableID.Hex() < tables[j].TableID.Hex()
+		})
+		for _, stats := range tables[:min(len(tables), busiestTablesPerDay)] {
+			stats.TableNumber = uc.tableNumber(ctx, tableNumbers, stats.TableID)
+			stats.ConversionRate = domain.ConversionRate(stats.Scans, stats.Requests)
+			scanDay.BusiestTables = append(scanDay.BusiestTables, *stats)
+		}
+
+		report.Days = append(report.Days, scanDay)
+	}
+
+	return report, nil
+}
+
+// tableNumber returns the current number of a table, caching lookups in numbers.
+// Deleted tables report 0.
+func (uc *requestUseCase) tableNumber(ctx context.Context, numbers map[primitive.ObjectID]int, tableID primitive.ObjectID) int {
+	if number, ok := numbers[tableID]; ok {
+		return number
+	}
+
+	number := 0
+	if table, err := uc.tableRepo.FindByID(ctx, tableID); err == nil {
+		number = table.Number
+	}
+	numbers[tableID] = number
+	return number
+}
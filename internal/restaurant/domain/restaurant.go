@@ -13,6 +13,9 @@ type Restaurant struct {
 	CUIT      string             `json:"cuit" bson:"cuit,unique"`
 	CreatedAt time.Time          `json:"createdAt" bson:"created_at"`
 	UpdatedAt time.Time          `json:"updatedAt" bson:"updated_at"`
+	// RequestRetentionDays is how long requests stay in the hot collection
+	// before being archived. 0 keeps them forever.
+	RequestRetentionDays int `json:"requestRetentionDays" bson:"request_retention_days,omitempty"`
 }
 
 // CreateRestaurantInput represents the data needed to create a restaurant
@@ -23,8 +26,9 @@ type CreateRestaurantInput struct {
 
 // UpdateRestaurantInput represents the data needed to update a restaurant
 type UpdateRestaurantInput struct {
-	Name string `json:"name,omitempty" binding:"omitempty,min=3,max=100"`
-	CUIT string `json:"cuit,omitempty" binding:"omitempty"`
+	Name                 string `json:"name,omitempty" binding:"omitempty,min=3,max=100"`
+	CUIT                 string `json:"cuit,omitempty" binding:"omitempty"`
+	RequestRetentionDays *int   `json:"requestRetentionDays,omitempty" binding:"omitempty,min=0,max=3650"`
 }
 
 // NewRestaurant creates a new restaurant with the current timestamp
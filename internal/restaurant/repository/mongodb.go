@@ -72,6 +72,25 @@ func (r *mongoRepository) FindByUserID(ctx context.Context, userID primitive.Obj
 	return restaurants, nil
 }
 
+// FindWithRequestRetention returns the restaurants that have a request retention period configured
+func (r *mongoRepository) FindWithRequestRetention(ctx context.Context) ([]*domain.Restaurant, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"request_retention_days": bson.M{"$gt": 0}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	restaurants := make([]*domain.Restaurant, 0)
+	if err := cursor.All(ctx, &restaurants); err != nil {
+		return nil, err
+	}
+
+	return restaurants, nil
+}
+
 func (r *mongoRepository) Update(ctx context.Context, restaurant *domain.Restaurant) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -80,8 +99,9 @@ func (r *mongoRepository) Update(ctx context.Context, restaurant *domain.Restaur
 
 	update := bson.M{
 		"$set": bson.M{
-			"name":       restaurant.Name,
-			"updated_at": restaurant.UpdatedAt,
+			"name":                   restaurant.Name,
+			"request_retention_days": restaurant.RequestRetentionDays,
+			"updated_at":             restaurant.UpdatedAt,
 		},
 	}
 
@@ -13,6 +13,7 @@ type Repository interface {
 	Create(ctx context.Context, restaurant *domain.Restaurant) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Restaurant, error)
 	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Restaurant, error)
+	FindWithRequestRetention(ctx context.Context) ([]*domain.Restaurant, error)
 	Update(ctx context.Context, restaurant *domain.Restaurant) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 }
@@ -137,6 +137,10 @@ func (uc *restaurantUseCase) Update(ctx context.Context, id primitive.ObjectID,
 		restaurant.Name = input.Name
 	}
 
+	if input.RequestRetentionDays != nil {
+		restaurant.RequestRetentionDays = *input.RequestRetentionDays
+	}
+
 	// Save changes
 	if err := uc.repo.Update(ctx, restaurant); err != nil {
 		return nil, err
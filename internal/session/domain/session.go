@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SessionStatus represents whether a table session is still running
+type SessionStatus string
+
+const (
+	StatusOpen   SessionStatus = "open"
+	StatusClosed SessionStatus = "closed"
+)
+
+// Reasons a session was closed
+const (
+	CloseReasonStaff        = "staff"
+	CloseReasonBillAttended = "bill_attended"
+)
+
+// TableSession is the occupancy of a table by one party, from the moment staff
+// seat them until they leave. A table has at most one open session.
+type TableSession struct {
+	ID           primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	RestaurantID primitive.ObjectID  `json:"restaurantId" bson:"restaurant_id"`
+	BranchID     primitive.ObjectID  `json:"branchId" bson:"branch_id"`
+	TableID      primitive.ObjectID  `json:"tableId" bson:"table_id"`
+	TableNumber  int                 `json:"tableNumber" bson:"table_number"`
+	PartySize    int                 `json:"partySize,omitempty" bson:"party_size,omitempty"`
+	Status       SessionStatus       `json:"status" bson:"status"`
+	OpenedBy     primitive.ObjectID  `json:"openedBy" bson:"opened_by"`
+	OpenedAt     time.Time           `json:"openedAt" bson:"opened_at"`
+	ClosedBy     *primitive.ObjectID `json:"closedBy,omitempty" bson:"closed_by,omitempty"`
+	ClosedAt     *time.Time          `json:"closedAt,omitempty" bson:"closed_at,omitempty"`
+	CloseReason  string              `json:"closeReason,omitempty" bson:"close_reason,omitempty"`
+	// DurationSeconds is how long the table was occupied, set when the session closes
+	DurationSeconds int64 `json:"durationSeconds,omitempty" bson:"duration_seconds,omitempty"`
+}
+
+// OpenSessionInput represents the data needed to seat a party at a table
+type OpenSessionInput struct {
+	TableID   string `json:"tableId" binding:"required,mongodb"`
+	PartySize int    `json:"partySize,omitempty" binding:"omitempty,min=1,max=100"`
+}
+
+// SessionFilter represents the optional query filters for session listings
+type SessionFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=open closed"`
+}
+
+// SessionMetricsFilter represents the date range of the session metrics
+type SessionMetricsFilter struct {
+	From time.Time `form:"from" time_format:"2006-01-02"`
+	To   time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+// TableSessionTotals are the closed sessions of a table and their summed duration
+type TableSessionTotals struct {
+	TableID         primitive.ObjectID `bson:"_id"`
+	Sessions        int64              `bson:"sessions"`
+	DurationSeconds int64              `bson:"duration_seconds"`
+}
+
+// TableSessionMetrics are the occupancy metrics of one table
+type TableSessionMetrics struct {
+	TableID            primitive.ObjectID `json:"tableId"`
+	TableNumber        int                `json:"tableNumber"`
+	Sessions           int64              `json:"sessions"`
+	AvgDurationMinutes float64            `json:"avgDurationMinutes"`
+	OccupiedMinutes    float64            `json:"occupiedMinutes"`
+	// TurnsPerDay is the average number of parties seated at the table per day
+	TurnsPerDay float64 `json:"turnsPerDay"`
+}
+
+// SessionMetrics reports session duration and table turnover of a branch over a
+// date range. Only closed sessions opened within the range are counted.
+type SessionMetrics struct {
+	BranchID           primitive.ObjectID `json:"branchId"`
+	From               string             `json:"from"`
+	To                 string             `json:"to"`
+	Sessions           int64              `json:"sessions"`
+	AvgDurationMinutes float64            `json:"avgDurationMinutes"`
+	// TurnsPerTablePerDay is the branch turnover: sessions per table per day
+	TurnsPerTablePerDay float64               `json:"turnsPerTablePerDay"`
+	Tables              []TableSessionMetrics `json:"tables"`
+}
+
+// NewTableSession opens a session for a table
+func NewTableSession(restaurantID, branchID, tableID primitive.ObjectID, tableNumber, partySize int, openedBy primitive.ObjectID) *TableSession {
+	return &TableSession{
+		RestaurantID: restaurantID,
+		BranchID:     branchID,
+		TableID:      tableID,
+		TableNumber:  tableNumber,
+		PartySize:    partySize,
+		Status:       StatusOpen,
+		OpenedBy:     openedBy,
+		OpenedAt:     time.Now(),
+	}
+}
+
+// Close ends the session. closedBy is nil when the system closes it.
+func (s *TableSession) Close(reason string, closedBy *primitive.ObjectID) {
+	now := time.Now()
+	s.Status = StatusClosed
+	s.ClosedAt = &now
+	s.ClosedBy = closedBy
+	s.CloseReason = reason
+	s.DurationSeconds = int64(now.Sub(s.OpenedAt).Seconds())
+}
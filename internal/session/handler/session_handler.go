@@ -0,0 +1,358 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"juansecalvinio/tepidolacuenta/internal/middleware"
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/session/domain"
+	"juansecalvinio/tepidolacuenta/internal/session/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Handler struct {
+	useCase usecase.UseCase
+}
+
+// NewSessionHandler creates a new table session handler
+func NewSessionHandler(useCase usecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// Open handles seating a party at a table
+// @Summary Open a table session
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.OpenSessionInput true "Session data"
+// @Success 201 {object} pkg.Response{data=domain.TableSession}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 409 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/sessions [post]
+func (h *Handler) Open(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	var input domain.OpenSessionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	session, err := h.useCase.Open(c.Request.Context(), userID, input, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this table", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this table", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrSessionAlreadyOpen) {
+			pkg.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to open session", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusCreated, "Session opened successfully", session)
+}
+
+// Close handles ending a table session
+// @Summary Close a table session
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} pkg.Response{data=domain.TableSession}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 409 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/sessions/{id}/close [post]
+func (h *Handler) Close(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid session ID", err)
+		return
+	}
+
+	session, err := h.useCase.Close(c.Request.Context(), sessionID, userID, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Session not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this session", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this session", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrSessionClosed) {
+			pkg.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to close session", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Session closed successfully", session)
+}
+
+// GetByID handles retrieving a session by ID
+// @Summary Get table session by ID
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} pkg.Response{data=domain.TableSession}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/sessions/{id} [get]
+func (h *Handler) GetByID(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid session ID", err)
+		return
+	}
+
+	session, err := h.useCase.GetByID(c.Request.Context(), sessionID, userID, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Session not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this session", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this session", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to get session", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Session retrieved successfully", session)
+}
+
+// ListByBranch handles retrieving the latest sessions of a branch
+// @Summary List table sessions by branch
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Param branchId path string true "Branch ID"
+// @Param status query string false "open or closed"
+// @Success 200 {object} pkg.Response{data=[]domain.TableSession}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/sessions/branch/{branchId} [get]
+func (h *Handler) ListByBranch(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("branchId"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	var filter domain.SessionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		pkg.BadRequestResponse(c, "Invalid filters", err)
+		return
+	}
+
+	sessions, err := h.useCase.GetByBranchID(c.Request.Context(), branchID, userID, filter, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to get sessions", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// GetMetrics handles the session duration and turnover report of a branch
+// @Summary Get table session metrics of a branch
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Param branchId path string true "Branch ID"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Success 200 {object} pkg.Response{data=domain.SessionMetrics}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/sessions/branch/{branchId}/metrics [get]
+func (h *Handler) GetMetrics(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("branchId"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	var filter domain.SessionMetricsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		pkg.BadRequestResponse(c, "Invalid filters", err)
+		return
+	}
+
+	metrics, err := h.useCase.GetMetrics(c.Request.Context(), branchID, userID, filter, extractRestaurantIDHint(c), extractBranchIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to get session metrics", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Session metrics retrieved successfully", metrics)
+}
+
+// extractRestaurantIDHint parses the employee's restaurantID from context (nil for owners).
+func extractRestaurantIDHint(c *gin.Context) *primitive.ObjectID {
+	ridStr, ok := middleware.GetUserRestaurantID(c)
+	if !ok {
+		return nil
+	}
+	rid, err := primitive.ObjectIDFromHex(ridStr)
+	if err != nil {
+		return nil
+	}
+	return &rid
+}
+
+// extractBranchIDHint parses the employee's branchID from context (nil for owners).
+func extractBranchIDHint(c *gin.Context) *primitive.ObjectID {
+	bidStr, ok := middleware.GetUserBranchID(c)
+	if !ok {
+		return nil
+	}
+	bid, err := primitive.ObjectIDFromHex(bidStr)
+	if err != nil {
+		return nil
+	}
+	return &bid
+}
+
+// RegisterRoutes registers all table session routes.
+// Staff seat and release tables, so every route is open to owners and employees.
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	sessions := router.Group("/sessions")
+	{
+		sessions.POST("", h.Open)
+		sessions.GET("/:id", h.GetByID)
+		sessions.POST("/:id/close", h.Close)
+		sessions.GET("/branch/:branchId", h.ListByBranch)
+		sessions.GET("/branch/:branchId/metrics", h.GetMetrics)
+	}
+}
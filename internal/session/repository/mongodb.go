@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/session/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB table session repository
+func NewMongoRepository(db *mongo.Database) Repository {
+	return &mongoRepository{
+		collection: db.Collection("table_sessions"),
+	}
+}
+
+func (r *mongoRepository) Create(ctx context.Context, session *domain.TableSession) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// A partial unique index on open sessions keeps two staff members from seating the same table
+	result, err := r.collection.InsertOne(ctx, session)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return pkg.ErrSessionAlreadyOpen
+		}
+		return err
+	}
+
+	session.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *mongoRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.TableSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var session domain.TableSession
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&session)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, pkg.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *mongoRepository) FindOpenByTableID(ctx context.Context, tableID primitive.ObjectID) (*domain.TableSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var session domain.TableSession
+	err := r.collection.FindOne(ctx, bson.M{"table_id": tableID, "status": domain.StatusOpen}).Decode(&session)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *mongoRepository) FindByBranchID(ctx context.Context, branchID primitive.ObjectID, status domain.SessionStatus, limit int64) ([]*domain.TableSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"branch_id": branchID}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "opened_at", Value: -1}}).SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := make([]*domain.TableSession, 0)
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *mongoRepository) Close(ctx context.Context, session *domain.TableSession) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":           session.Status,
+			"closed_by":        session.ClosedBy,
+			"closed_at":        session.ClosedAt,
+			"close_reason":     session.CloseReason,
+			"duration_seconds": session.DurationSeconds,
+		},
+	}
+
+	// Only an open session can be closed, so a concurrent close loses cleanly
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": session.ID, "status": domain.StatusOpen}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return pkg.ErrSessionClosed
+	}
+
+	return nil
+}
+
+func (r *mongoRepository) SumClosedByTable(ctx context.Context, branchID primitive.ObjectID, from, to time.Time) ([]domain.TableSessionTotals, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"branch_id": branchID,
+			"status":    domain.StatusClosed,
+			"opened_at": bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":              "$table_id",
+			"sessions":         bson.M{"$sum": 1},
+			"duration_seconds": bson.M{"$sum": "$duration_seconds"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	totals := make([]domain.TableSessionTotals, 0)
+	if err := cursor.All(ctx, &totals); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"juansecalvinio/tepidolacuenta/internal/session/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Repository defines the interface for table session persistence operations
+type Repository interface {
+	// Create returns pkg.ErrSessionAlreadyOpen if the table already has an open session
+	Create(ctx context.Context, session *domain.TableSession) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.TableSession, error)
+	// FindOpenByTableID returns nil if the table has no open session
+	FindOpenByTableID(ctx context.Context, tableID primitive.ObjectID) (*domain.TableSession, error)
+	// FindByBranchID lists the sessions of a branch, newest first. An empty status lists
+	// all of them and a zero limit returns every match.
+	FindByBranchID(ctx context.Context, branchID primitive.ObjectID, status domain.SessionStatus, limit int64) ([]*domain.TableSession, error)
+	// Close stores a closed session. Returns pkg.ErrSessionClosed if it was already closed.
+	Close(ctx context.Context, session *domain.TableSession) error
+	// SumClosedByTable totals the closed sessions of a branch opened in [from, to) per table
+	SumClosedByTable(ctx context.Context, branchID primitive.ObjectID, from, to time.Time) ([]domain.TableSessionTotals, error)
+}
@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	branchRepo "juansecalvinio/tepidolacuenta/internal/branch/repository"
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	restaurantRepo "juansecalvinio/tepidolacuenta/internal/restaurant/repository"
+	"juansecalvinio/tepidolacuenta/internal/session/domain"
+	"juansecalvinio/tepidolacuenta/internal/session/repository"
+	tableRepo "juansecalvinio/tepidolacuenta/internal/table/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session listing and metrics limits
+const (
+	maxSessionsListed  = 200
+	defaultMetricsDays = 30
+	maxMetricsDays     = 92
+	metricsDateLayout  = "2006-01-02"
+)
+
+// UseCase defines the interface for table session use cases
+type UseCase interface {
+	Open(ctx context.Context, userID primitive.ObjectID, input domain.OpenSessionInput, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.TableSession, error)
+	Close(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.TableSession, error)
+	GetByID(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.TableSession, error)
+	GetByBranchID(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, filter domain.SessionFilter, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]*domain.TableSession, error)
+	GetMetrics(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, filter domain.SessionMetricsFilter, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.SessionMetrics, error)
+}
+
+type sessionUseCase struct {
+	repo           repository.Repository
+	branchRepo     branchRepo.Repository
+	restaurantRepo restaurantRepo.Repository
+	tableRepo      tableRepo.Repository
+}
+
+// NewSessionUseCase creates a new table session use case
+func NewSessionUseCase(
+	repo repository.Repository,
+	branchRepo branchRepo.Repository,
+	restaurantRepo restaurantRepo.Repository,
+	tableRepo tableRepo.Repository,
+) UseCase {
+	return &sessionUseCase{
+		repo:           repo,
+		branchRepo:     branchRepo,
+		restaurantRepo: restaurantRepo,
+		tableRepo:      tableRepo,
+	}
+}
+
+// verifyBranchAccess verifies that a branch exists and the caller can access it,
+// returning its restaurant ID. Branch-scoped employees only reach their own branch.
+func (uc *sessionUseCase) verifyBranchAccess(ctx context.Context, branchID, userID primitive.ObjectID, restaurantIDHint, branchIDHint *primitive.ObjectID) (primitive.ObjectID, error) {
+	branch, err := uc.branchRepo.FindByID(ctx, branchID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, branch.RestaurantID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	if restaurantIDHint != nil {
+		if restaurant.ID != *restaurantIDHint {
+			return primitive.NilObjectID, pkg.ErrForbidden
+		}
+	} else {
+		if restaurant.UserID != userID {
+			return primitive.NilObjectID, pkg.ErrUnauthorized
+		}
+	}
+
+	if branchIDHint != nil && branch.ID != *branchIDHint {
+		return primitive.NilObjectID, pkg.ErrForbidden
+	}
+
+	return restaurant.ID, nil
+}
+
+// Open seats a party at a table
+func (uc *sessionUseCase) Open(ctx context.Context, userID primitive.ObjectID, input domain.OpenSessionInput, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.TableSession, error) {
+	tableID, err := primitive.ObjectIDFromHex(input.TableID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid table ID", pkg.ErrInvalidInput)
+	}
+
+	table, err := uc.tableRepo.FindByID(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurantID, err := uc.verifyBranchAccess(ctx, table.BranchID, userID, restaurantIDHint, branchIDHint)
+	if err != nil {
+		return nil, err
+	}
+
+	if !table.IsActive {
+		return nil, fmt.Errorf("%w: table %d is not active", pkg.ErrInvalidInput, table.Number)
+	}
+
+	// Merged tables share the session of the table they were merged into
+	if table.MergedIntoTableID != nil {
+		return nil, fmt.Errorf("%w: table %d is merged into another table, open the session there", pkg.ErrInvalidInput, table.Number)
+	}
+
+	session := domain.NewTableSession(restaurantID, table.BranchID, table.ID, table.Number, input.PartySize, userID)
+	if err := uc.repo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Close ends a session when the party leaves
+func (uc *sessionUseCase) Close(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.TableSession, error) {
+	session, err := uc.GetByID(ctx, id, userID, restaurantIDHint, branchIDHint)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Status != domain.StatusOpen {
+		return nil, pkg.ErrSessionClosed
+	}
+
+	session.Close(domain.CloseReasonStaff, &userID)
+	if err := uc.repo.Close(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetByID retrieves a session by ID
+func (uc *sessionUseCase) GetByID(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.TableSession, error) {
+	session, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.verifyBranchAccess(ctx, session.BranchID, userID, restaurantIDHint, branchIDHint); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetByBranchID retrieves the latest sessions of a branch, newest first
+func (uc *sessionUseCase) GetByBranchID(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, filter domain.SessionFilter, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) ([]*domain.TableSession, error) {
+	if _, err := uc.verifyBranchAccess(ctx, branchID, userID, restaurantIDHint, branchIDHint); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.FindByBranchID(ctx, branchID, domain.SessionStatus(filter.Status), maxSessionsListed)
+}
+
+// GetMetrics reports session durations and table turnover of a branch per table.
+// Days are UTC and the range defaults to the last 30 days.
+func (uc *sessionUseCase) GetMetrics(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, filter domain.SessionMetricsFilter, restaurantIDHint *primitive.ObjectID, branchIDHint *primitive.ObjectID) (*domain.SessionMetrics, error) {
+	if _, err := uc.verifyBranchAccess(ctx, branchID, userID, restaurantIDHint, branchIDHint); err != nil {
+		return nil, err
+	}
+
+	to := filter.To
+	if to.IsZero() {
+		to = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	from := filter.From
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -(defaultMetricsDays - 1))
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: from must not be after to", pkg.ErrInvalidInput)
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > maxMetricsDays {
+		return nil, fmt.Errorf("%w: the date range can't exceed %d days", pkg.ErrInvalidInput, maxMetricsDays)
+	}
+
+	// The "to" date is inclusive for callers, so count up to the start of the next day
+	totals, err := uc.repo.SumClosedByTable(ctx, branchID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := uc.tableRepo.FindByBranchID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every active table is listed, idle ones included; tables that had sessions
+	// and were deactivated or deleted since are listed too
+	byTable := make(map[primitive.ObjectID]*domain.TableSessionMetrics)
+	active := make(map[primitive.ObjectID]bool)
+	for _, table := range tables {
+		if table.IsActive {
+			active[table.ID] = true
+		}
+		byTable[table.ID] = &domain.TableSessionMetrics{TableID: table.ID, TableNumber: table.Number}
+	}
+
+	metrics := &domain.SessionMetrics{
+		BranchID: branchID,
+		From:     from.Format(metricsDateLayout),
+		To:       to.Format(metricsDateLayout),
+		Tables:   make([]domain.TableSessionMetrics, 0, len(active)),
+	}
+
+	var totalSeconds int64
+	for _, t := range totals {
+		stats, ok := byTable[t.TableID]
+		if !ok {
+			stats = &domain.TableSessionMetrics{TableID: t.TableID}
+			byTable[t.TableID] = stats
+		}
+		stats.Sessions = t.Sessions
+		stats.OccupiedMinutes = float64(t.DurationSeconds) / 60
+		stats.AvgDurationMinutes = stats.OccupiedMinutes / float64(t.Sessions)
+		stats.TurnsPerDay = float64(t.Sessions) / float64(days)
+
+		metrics.Sessions += t.Sessions
+		totalSeconds += t.DurationSeconds
+	}
+
+	if metrics.Sessions > 0 {
+		metrics.AvgDurationMinutes = float64(totalSeconds) / 60 / float64(metrics.Sessions)
+	}
+	if len(active) > 0 {
+		metrics.TurnsPerTablePerDay = float64(metrics.Sessions) / float64(len(active)) / float64(days)
+	}
+
+	for id, stats := range byTable {
+		if active[id] || stats.Sessions > 0 {
+			metrics.Tables = append(metrics.Tables, *stats)
+		}
+	}
+	sort.Slice(metrics.Tables, func(i, j int) bool {
+		if metrics.Tables[i].TableNumber != metrics.Tables[j].TableNumber {
+			return metrics.Tables[i].TableNumber < metrics.Tables[j].TableNumber
+		}
+		return metrics.Tables[i].TableID.Hex() < metrics.Tables[j].TableID.Hex()
+	})
+
+	return metrics, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	branchDomain "juansecalvinio/tepidolacuenta/internal/branch/domain"
 	branchRepo "juansecalvinio/tepidolacuenta/internal/branch/repository"
+	"juansecalvinio/tepidolacuenta/internal/database"
 	"juansecalvinio/tepidolacuenta/internal/pkg"
 	restaurantDomain "juansecalvinio/tepidolacuenta/internal/restaurant/domain"
 	restaurantRepo "juansecalvinio/tepidolacuenta/internal/restaurant/repository"
@@ -25,6 +26,8 @@ type setupUseCase struct {
 	restaurantRepo restaurantRepo.Repository
 	branchRepo     branchRepo.Repository
 	tableRepo      tableRepo.Repository
+	shortLinkRepo  tableRepo.ShortLinkRepository
+	tx             database.Transactor
 	qrService      *pkg.QRService
 }
 
@@ -33,68 +36,64 @@ func NewSetupUseCase(
 	restaurantRepo restaurantRepo.Repository,
 	branchRepo branchRepo.Repository,
 	tableRepo tableRepo.Repository,
+	shortLinkRepo tableRepo.ShortLinkRepository,
+	tx database.Transactor,
 	qrService *pkg.QRService,
 ) UseCase {
 	return &setupUseCase{
 		restaurantRepo: restaurantRepo,
 		branchRepo:     branchRepo,
 		tableRepo:      tableRepo,
+		shortLinkRepo:  shortLinkRepo,
+		tx:             tx,
 		qrService:      qrService,
 	}
 }
 
-// SetupRestaurant creates a restaurant, its first branch, and tables in a single operation
+// SetupRestaurant creates a restaurant, its first branch, and tables in a single
+// transaction, so a failure at any step leaves nothing behind
 func (uc *setupUseCase) SetupRestaurant(ctx context.Context, userID primitive.ObjectID, input domain.SetupRestaurantInput) (*domain.SetupRestaurantOutput, error) {
-	// Step 1: Create restaurant
-	restaurant := restaurantDomain.NewRestaurant(userID, input.Name, input.CUIT)
-	if err := uc.restaurantRepo.Create(ctx, restaurant); err != nil {
-		return nil, fmt.Errorf("failed to create restaurant: %w", err)
-	}
-
-	// Step 2: Create branch linked to the new restaurant
-	branch := branchDomain.NewBranch(restaurant.ID, input.Address, "")
-	if err := uc.branchRepo.Create(ctx, branch); err != nil {
-		_ = uc.restaurantRepo.Delete(ctx, restaurant.ID)
-		return nil, fmt.Errorf("failed to create branch: %w", err)
-	}
+	var output *domain.SetupRestaurantOutput
 
-	// Step 3: Create tables linked to the new branch
-	tables := make([]*tableDomain.Table, 0, input.TableCount)
-	for i := 1; i <= input.TableCount; i++ {
-		newTable := tableDomain.NewTable(branch.ID, i, "")
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Step 1: Create restaurant
+		restaurant := restaurantDomain.NewRestaurant(userID, input.Name, input.CUIT)
+		if err := uc.restaurantRepo.Create(ctx, restaurant); err != nil {
+			return fmt.Errorf("failed to create restaurant: %w", err)
+		}
 
-		if err := uc.tableRepo.Create(ctx, newTable); err != nil {
-			uc.cleanupTables(ctx, tables)
-			_ = uc.branchRepo.Delete(ctx, branch.ID)
-			_ = uc.restaurantRepo.Delete(ctx, restaurant.ID)
-			return nil, fmt.Errorf("failed to create table %d: %w", i, err)
+		// Step 2: Create branch linked to the new restaurant
+		branch := branchDomain.NewBranch(restaurant.ID, input.Address, "")
+		if err := uc.branchRepo.Create(ctx, branch); err != nil {
+			return fmt.Errorf("failed to create branch: %w", err)
 		}
 
-		// Generate QR code with the actual table ID
-		qrCode := uc.qrService.GenerateTableQRCode(restaurant.ID, branch.ID, newTable.ID, i)
-		newTable.QRCode = qrCode
+		// Step 3: Build the tables and their short links up front and insert each batch at once
+		tables := make([]*tableDomain.Table, 0, input.TableCount)
+		links := make([]*tableDomain.ShortLink, 0, input.TableCount)
+		for i := 1; i <= input.TableCount; i++ {
+			newTable := tableDomain.NewTableWithQR(uc.qrService, branch.ID, i)
+			tables = append(tables, newTable)
+			links = append(links, tableDomain.NewTableShortLink(uc.qrService, newTable))
+		}
 
-		if err := uc.tableRepo.Update(ctx, newTable); err != nil {
-			uc.cleanupTables(ctx, tables)
-			_ = uc.tableRepo.Delete(ctx, newTable.ID)
-			_ = uc.branchRepo.Delete(ctx, branch.ID)
-			_ = uc.restaurantRepo.Delete(ctx, restaurant.ID)
-			return nil, fmt.Errorf("failed to update QR code for table %d: %w", i, err)
+		if err := uc.shortLinkRepo.CreateMany(ctx, links); err != nil {
+			return fmt.Errorf("failed to create short links: %w", err)
+		}
+		if err := uc.tableRepo.CreateMany(ctx, tables); err != nil {
+			return fmt.Errorf("failed to create tables: %w", err)
 		}
 
-		tables = append(tables, newTable)
+		output = &domain.SetupRestaurantOutput{
+			Restaurant: restaurant,
+			Branch:     branch,
+			Tables:     tables,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &domain.SetupRestaurantOutput{
-		Restaurant: restaurant,
-		Branch:     branch,
-		Tables:     tables,
-	}, nil
-}
-
-// cleanupTables deletes all tables that were created during a failed setup
-func (uc *setupUseCase) cleanupTables(ctx context.Context, tables []*tableDomain.Table) {
-	for _, t := range tables {
-		_ = uc.tableRepo.Delete(ctx, t.ID)
-	}
+	return output, nil
 }
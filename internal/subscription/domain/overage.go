@@ -0,0 +1,35 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// PlanChangeParams are the query params of a plan change pre-check
+type PlanChangeParams struct {
+	PlanID string `form:"planId" binding:"required,mongodb"`
+}
+
+// PlanOverage lists what a restaurant has active beyond a plan's limits and
+// would be deactivated when switching to it. The oldest branches and the
+// lowest numbered tables of each branch are the ones kept active.
+type PlanOverage struct {
+	PlanID         primitive.ObjectID `json:"planId"`
+	PlanName       string             `json:"planName"`
+	MaxBranches    int                `json:"maxBranches"`
+	MaxTables      int                `json:"maxTables"`
+	ActiveBranches int                `json:"activeBranches"`
+	WithinLimits   bool               `json:"withinLimits"`
+	ExcessBranches []OverageBranch    `json:"excessBranches"`
+	ExcessTables   []OverageTable     `json:"excessTables"`
+}
+
+// OverageBranch is an active branch beyond the plan's branch limit
+type OverageBranch struct {
+	BranchID primitive.ObjectID `json:"branchId"`
+	Address  string             `json:"address"`
+}
+
+// OverageTable is an active table beyond the plan's per-branch table limit
+type OverageTable struct {
+	TableID  primitive.ObjectID `json:"tableId"`
+	BranchID primitive.ObjectID `json:"branchId"`
+	Number   int                `json:"number"`
+}
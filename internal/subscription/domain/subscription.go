@@ -64,6 +64,9 @@ type UpdateSubscriptionInput struct {
 	PlanID                string `json:"planId,omitempty"`
 	Status                string `json:"status,omitempty" binding:"omitempty,oneof=active trialing canceled expired past_due"`
 	PaymentSubscriptionID string `json:"paymentSubscriptionId,omitempty"`
+	// ReconcileOverage confirms a plan change that leaves the restaurant over the
+	// new limits; the excess branches and tables are deactivated
+	ReconcileOverage bool `json:"reconcileOverage,omitempty"`
 }
 
 // NewPlan creates a new plan with the current timestamp
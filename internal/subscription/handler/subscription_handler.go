@@ -197,6 +197,52 @@ func (h *Handler) GetByRestaurant(c *gin.Context) {
 	pkg.SuccessResponse(c, http.StatusOK, "Subscription retrieved successfully", subscription)
 }
 
+// CheckPlanChange handles the pre-check of a plan change, listing the branches
+// and tables the new plan would deactivate
+func (h *Handler) CheckPlanChange(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	subscriptionIDStr := c.Param("id")
+	subscriptionID, err := primitive.ObjectIDFromHex(subscriptionIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid subscription ID", err)
+		return
+	}
+
+	var params domain.PlanChangeParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		pkg.BadRequestResponse(c, "Invalid plan ID", err)
+		return
+	}
+	planID, _ := primitive.ObjectIDFromHex(params.PlanID)
+
+	overage, err := h.useCase.CheckPlanChange(c.Request.Context(), subscriptionID, userID, planID)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Subscription or plan not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this subscription", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to check plan change", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Plan change checked successfully", overage)
+}
+
 // Update handles subscription updates
 func (h *Handler) Update(c *gin.Context) {
 	userIDStr, exists := middleware.GetUserID(c)
@@ -234,6 +280,10 @@ func (h *Handler) Update(c *gin.Context) {
 			pkg.UnauthorizedResponse(c, "You don't have access to this subscription", err)
 			return
 		}
+		if errors.Is(err, pkg.ErrPlanOverage) {
+			pkg.ErrorResponse(c, http.StatusConflict, "The new plan allows fewer active branches or tables than the restaurant has. Check the plan change and confirm it with reconcileOverage", err)
+			return
+		}
 		pkg.InternalServerErrorResponse(c, "Failed to update subscription", err)
 		return
 	}
@@ -301,6 +351,7 @@ func (h *Handler) RegisterRoutes(protected *gin.RouterGroup, public *gin.RouterG
 		subscriptions.GET("", h.GetByUser)
 		subscriptions.GET("/:id", h.GetByID)
 		subscriptions.GET("/restaurant/:restaurantId", h.GetByRestaurant)
+		subscriptions.GET("/:id/plan-change", h.CheckPlanChange)
 		subscriptions.PUT("/:id", h.Update)
 		subscriptions.DELETE("/:id", h.Cancel)
 	}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/subscription/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CheckPlanChange reports which branches and tables would be deactivated if the
+// subscription switched to the given plan. Nothing is changed.
+func (uc *subscriptionUseCase) CheckPlanChange(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, planID primitive.ObjectID) (*domain.PlanOverage, error) {
+	subscription, err := uc.subscriptionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.UserID != userID {
+		return nil, pkg.ErrUnauthorized
+	}
+
+	plan, err := uc.planRepo.FindByID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.planOverage(ctx, subscription.RestaurantID, plan)
+}
+
+// planOverage lists the restaurant's active branches and tables beyond the plan's
+// limits. The oldest branches and the lowest numbered tables of each branch are
+// kept; tables are checked in every branch, so a branch reactivated later is
+// already within the table limit.
+func (uc *subscriptionUseCase) planOverage(ctx context.Context, restaurantID primitive.ObjectID, plan *domain.Plan) (*domain.PlanOverage, error) {
+	branches, err := uc.branchRepo.FindByRestaurantID(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(branches, func(i, j int) bool {
+		if branches[i].CreatedAt.Equal(branches[j].CreatedAt) {
+			return branches[i].ID.Hex() < branches[j].ID.Hex()
+		}
+		return branches[i].CreatedAt.Before(branches[j].CreatedAt)
+	})
+
+	overage := &domain.PlanOverage{
+		PlanID:         plan.ID,
+		PlanName:       plan.Name,
+		MaxBranches:    plan.MaxBranches,
+		MaxTables:      plan.MaxTables,
+		ExcessBranches: make([]domain.OverageBranch, 0),
+		ExcessTables:   make([]domain.OverageTable, 0),
+	}
+
+	for _, branch := range branches {
+		if branch.IsActive {
+			overage.ActiveBranches++
+			if plan.MaxBranches != domain.Unlimited && overage.ActiveBranches > plan.MaxBranches {
+				overage.ExcessBranches = append(overage.ExcessBranches, domain.OverageBranch{
+					BranchID: branch.ID,
+					Address:  branch.Address,
+				})
+			}
+		}
+
+		if plan.MaxTables == domain.Unlimited {
+			continue
+		}
+
+		tables, err := uc.tableRepo.FindByBranchID(ctx, branch.ID)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(tables, func(i, j int) bool { return tables[i].Number < tables[j].Number })
+
+		active := 0
+		for _, table := range tables {
+			if !table.IsActive {
+				continue
+			}
+			active++
+			if active > plan.MaxTables {
+				overage.ExcessTables = append(overage.ExcessTables, domain.OverageTable{
+					TableID:  table.ID,
+					BranchID: branch.ID,
+					Number:   table.Number,
+				})
+			}
+		}
+	}
+
+	overage.WithinLimits = len(overage.ExcessBranches) == 0 && len(overage.ExcessTables) == 0
+	return overage, nil
+}
+
+// reconcileOverage deactivates the excess branches and tables of an overage
+func (uc *subscriptionUseCase) reconcileOverage(ctx context.Context, overage *domain.PlanOverage) error {
+	if len(overage.ExcessBranches) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(overage.ExcessBranches))
+		for _, branch := range overage.ExcessBranches {
+			ids = append(ids, branch.BranchID)
+		}
+		if err := uc.branchRepo.SetActive(ctx, ids, false); err != nil {
+			return fmt.Errorf("failed to deactivate branches: %w", err)
+		}
+	}
+
+	if len(overage.ExcessTables) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(overage.ExcessTables))
+		for _, table := range overage.ExcessTables {
+			ids = append(ids, table.TableID)
+		}
+		if err := uc.tableRepo.SetActive(ctx, ids, false); err != nil {
+			return fmt.Errorf("failed to deactivate tables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ActivatePaidPlan activates the restaurant's subscription on a plan paid through
+// Mercado Pago, creating the subscription if there's no current one. The payment
+// is already taken, so instead of rejecting a downgrade the excess branches and
+// tables are deactivated, in the same transaction as the plan change.
+func (uc *subscriptionUseCase) ActivatePaidPlan(ctx context.Context, userID primitive.ObjectID, restaurantID primitive.ObjectID, planID primitive.ObjectID, paymentRef string) error {
+	plan, err := uc.planRepo.FindByID(ctx, planID)
+	if err != nil {
+		return err
+	}
+
+	return uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		overage, err := uc.planOverage(ctx, restaurantID, plan)
+		if err != nil {
+			return err
+		}
+		if !overage.WithinLimits {
+			if err := uc.reconcileOverage(ctx, overage); err != nil {
+				return err
+			}
+		}
+
+		existing, err := uc.subscriptionRepo.FindByRestaurantID(ctx, restaurantID)
+		if err == nil && existing != nil &&
+			existing.Status != domain.SubscriptionStatusCanceled &&
+			existing.Status != domain.SubscriptionStatusExpired {
+			existing.PlanID = planID
+			existing.Status = domain.SubscriptionStatusActive
+			existing.PaymentSubscriptionID = paymentRef
+			return uc.subscriptionRepo.Update(ctx, existing)
+		}
+
+		subscription := domain.NewSubscription(userID, restaurantID, planID, domain.SubscriptionStatusActive)
+		subscription.PaymentSubscriptionID = paymentRef
+		return uc.subscriptionRepo.Create(ctx, subscription)
+	})
+}
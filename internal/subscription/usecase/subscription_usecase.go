@@ -5,10 +5,13 @@ import (
 	"errors"
 	"time"
 
+	branchRepo "juansecalvinio/tepidolacuenta/internal/branch/repository"
+	"juansecalvinio/tepidolacuenta/internal/database"
 	"juansecalvinio/tepidolacuenta/internal/pkg"
 	restaurantRepo "juansecalvinio/tepidolacuenta/internal/restaurant/repository"
 	"juansecalvinio/tepidolacuenta/internal/subscription/domain"
 	"juansecalvinio/tepidolacuenta/internal/subscription/repository"
+	tableRepo "juansecalvinio/tepidolacuenta/internal/table/repository"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -24,14 +27,21 @@ type UseCase interface {
 	GetByID(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*domain.SubscriptionWithPlan, error)
 	GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Subscription, error)
 	GetByRestaurantID(ctx context.Context, restaurantID primitive.ObjectID, userID primitive.ObjectID) (*domain.SubscriptionWithPlan, error)
+	// CheckPlanChange reports what switching the subscription to another plan would deactivate
+	CheckPlanChange(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, planID primitive.ObjectID) (*domain.PlanOverage, error)
 	Update(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.UpdateSubscriptionInput) (*domain.Subscription, error)
 	Cancel(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) error
+	// ActivatePaidPlan puts the restaurant on a plan it has paid for
+	ActivatePaidPlan(ctx context.Context, userID primitive.ObjectID, restaurantID primitive.ObjectID, planID primitive.ObjectID, paymentRef string) error
 }
 
 type subscriptionUseCase struct {
 	planRepo         repository.PlanRepository
 	subscriptionRepo repository.SubscriptionRepository
 	restaurantRepo   restaurantRepo.Repository
+	branchRepo       branchRepo.Repository
+	tableRepo        tableRepo.Repository
+	tx               database.Transactor
 }
 
 // NewSubscriptionUseCase creates a new subscription use case
@@ -39,11 +49,17 @@ func NewSubscriptionUseCase(
 	planRepo repository.PlanRepository,
 	subscriptionRepo repository.SubscriptionRepository,
 	restaurantRepo restaurantRepo.Repository,
+	branchRepo branchRepo.Repository,
+	tableRepo tableRepo.Repository,
+	tx database.Transactor,
 ) UseCase {
 	return &subscriptionUseCase{
 		planRepo:         planRepo,
 		subscriptionRepo: subscriptionRepo,
 		restaurantRepo:   restaurantRepo,
+		branchRepo:       branchRepo,
+		tableRepo:        tableRepo,
+		tx:               tx,
 	}
 }
 
@@ -204,14 +220,18 @@ func (uc *subscriptionUseCase) Update(ctx context.Context, id primitive.ObjectID
 		return nil, pkg.ErrUnauthorized
 	}
 
+	planChanged := false
+	var plan *domain.Plan
 	if input.PlanID != "" {
 		planID, err := primitive.ObjectIDFromHex(input.PlanID)
 		if err != nil {
 			return nil, errors.New("invalid plan ID")
 		}
-		if _, err := uc.planRepo.FindByID(ctx, planID); err != nil {
+		plan, err = uc.planRepo.FindByID(ctx, planID)
+		if err != nil {
 			return nil, err
 		}
+		planChanged = planID != subscription.PlanID
 		subscription.PlanID = planID
 	}
 
@@ -223,7 +243,33 @@ func (uc *subscriptionUseCase) Update(ctx context.Context, id primitive.ObjectID
 		subscription.PaymentSubscriptionID = input.PaymentSubscriptionID
 	}
 
-	if err := uc.subscriptionRepo.Update(ctx, subscription); err != nil {
+	if !planChanged {
+		if err := uc.subscriptionRepo.Update(ctx, subscription); err != nil {
+			return nil, err
+		}
+		return subscription, nil
+	}
+
+	// A plan with lower limits can leave the restaurant over them. The change is
+	// rejected unless the caller agrees to deactivate the excess. The overage is
+	// computed inside the transaction so a branch or table activated meanwhile
+	// is counted.
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		overage, err := uc.planOverage(ctx, subscription.RestaurantID, plan)
+		if err != nil {
+			return err
+		}
+		if !overage.WithinLimits {
+			if !input.ReconcileOverage {
+				return pkg.ErrPlanOverage
+			}
+			if err := uc.reconcileOverage(ctx, overage); err != nil {
+				return err
+			}
+		}
+		return uc.subscriptionRepo.Update(ctx, subscription)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,34 @@
+package domain
+
+// MaxImportRows is the most tables a single CSV import can create or update
+const MaxImportRows = 500
+
+// Actions taken by a table import row
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+)
+
+// TableImportParams are the query params of a table CSV import
+type TableImportParams struct {
+	// DryRun validates the file and reports what would change without writing anything
+	DryRun bool `form:"dryRun"`
+}
+
+// TableImportRow is the outcome of one CSV row. Line is the line number in the file.
+type TableImportRow struct {
+	Line   int      `json:"line"`
+	Number int      `json:"number,omitempty"`
+	Action string   `json:"action,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// TableImportReport reports a table CSV import. Nothing is written unless every
+// row is valid; Created and Updated count what was (or, on a dry run, would be) done.
+type TableImportReport struct {
+	DryRun  bool             `json:"dryRun"`
+	Valid   bool             `json:"valid"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Rows    []TableImportRow `json:"rows"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// QRIssuer issues the tokens, signed URLs and short link slugs of table QR codes.
+// pkg.QRService implements it.
+type QRIssuer interface {
+	GenerateTableToken() string
+	GenerateTableQRCode(token string, version int) string
+	GenerateSlug() string
+	ShortLinkURL(slug string) string
+}
+
+// NewTableWithQR creates a table with a fresh token and the QR code pointing at it.
+// It still needs a short link, see NewTableShortLink.
+func NewTableWithQR(qr QRIssuer, branchID primitive.ObjectID, number int) *Table {
+	token := qr.GenerateTableToken()
+	table := NewTable(branchID, number, qr.GenerateTableQRCode(token, 0))
+	table.Token = token
+	return table
+}
+
+// NewTableShortLink creates a short link for the table's current QR version and
+// points the table at it. Neither is stored.
+func NewTableShortLink(qr QRIssuer, table *Table) *ShortLink {
+	link := NewShortLink(qr.GenerateSlug(), table.Token, table.QRVersion)
+	table.PointAtShortLink(qr, link)
+	return link
+}
+
+// PointAtShortLink sets the table's short link fields from the link
+func (t *Table) PointAtShortLink(qr QRIssuer, link *ShortLink) {
+	t.Slug = link.Slug
+	t.ShortURL = qr.ShortLinkURL(link.Slug)
+}
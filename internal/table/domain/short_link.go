@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShortLink maps a short QR code slug to a table. Each QR rotation issues a new
+// link; older links stay around so scans of revoked stickers can be told apart
+// from unknown codes.
+type ShortLink struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Slug          string             `json:"slug" bson:"slug"`
+	TableToken    string             `json:"-" bson:"table_token"`
+	QRVersion     int                `json:"qrVersion" bson:"qr_version"`
+	ScanCount     int64              `json:"scanCount" bson:"scan_count"`
+	LastScannedAt *time.Time         `json:"lastScannedAt,omitempty" bson:"last_scanned_at,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// NewShortLink creates a short link for the given table token and QR version
+func NewShortLink(slug, tableToken string, qrVersion int) *ShortLink {
+	return &ShortLink{
+		Slug:       slug,
+		TableToken: tableToken,
+		QRVersion:  qrVersion,
+		CreatedAt:  time.Now(),
+	}
+}
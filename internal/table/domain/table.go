@@ -1,31 +1,183 @@
 package domain
 
 import (
+	"io"
+	"strconv"
 	"time"
 
+	"juansecalvinio/tepidolacuenta/internal/pkg/ndef"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Table struct {
-	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	BranchID  primitive.ObjectID `json:"branchId" bson:"branch_id"`
-	Number    int                `json:"number" bson:"number"`
-	QRCode    string             `json:"qrCode" bson:"qr_code"`
-	IsActive  bool               `json:"isActive" bson:"is_active"`
-	CreatedAt time.Time          `json:"createdAt" bson:"created_at"`
-	UpdatedAt time.Time          `json:"updatedAt" bson:"updated_at"`
+	ID       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	BranchID primitive.ObjectID `json:"branchId" bson:"branch_id"`
+	Number   int                `json:"number" bson:"number"`
+	// Label is an optional display name ("Barra 3", "Box VIP") shown instead of
+	// the number. It is unique within the branch.
+	Label string `json:"label,omitempty" bson:"label,omitempty"`
+	// Capacity is the number of seats, 0 when unknown
+	Capacity int `json:"capacity,omitempty" bson:"capacity,omitempty"`
+	// Token is the opaque, immutable identifier printed in the table's QR code.
+	// The number shown to guests is looked up from it when the code is scanned.
+	Token string `json:"token" bson:"token,omitempty"`
+	// QRVersion is signed into the QR code. Rotating it invalidates every
+	// sticker printed with an older version.
+	QRVersion int    `json:"qrVersion" bson:"qr_version"`
+	QRCode    string `json:"qrCode" bson:"qr_code"`
+	// Slug is the table's current short link code and ShortURL the link built
+	// from it. Printed QR codes encode ShortURL, which is much shorter than QRCode.
+	Slug     string `json:"slug,omitempty" bson:"slug,omitempty"`
+	ShortURL string `json:"shortUrl,omitempty" bson:"short_url,omitempty"`
+	IsActive bool   `json:"isActive" bson:"is_active"`
+	// Priority marks tables (terrace, private room) whose requests are served first
+	Priority bool `json:"priority" bson:"priority"`
+	// ZoneID is the branch zone (salon, terrace, bar) the table belongs to, if any
+	ZoneID *primitive.ObjectID `json:"zoneId,omitempty" bson:"zone_id,omitempty"`
+	// Layout places the table on the branch floor plan; nil while it isn't placed
+	Layout *TableLayout `json:"layout,omitempty" bson:"layout,omitempty"`
+	// MergedIntoTableID is set while the table is joined to another one for a
+	// seating. Requests scanned from this table are created on the target table.
+	MergedIntoTableID *primitive.ObjectID `json:"mergedIntoTableId,omitempty" bson:"merged_into_table_id,omitempty"`
+	// FlaggedAt is set when the table's public endpoints exceed the rate limits,
+	// so the owner can review it. It stays set until the owner clears it.
+	FlaggedAt  *time.Time `json:"flaggedAt,omitempty" bson:"flagged_at,omitempty"`
+	FlagReason string     `json:"flagReason,omitempty" bson:"flag_reason,omitempty"`
+	// PublicBlockedUntil rejects new public requests for the table until that time
+	PublicBlockedUntil *time.Time `json:"publicBlockedUntil,omitempty" bson:"public_blocked_until,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt" bson:"created_at"`
+	UpdatedAt          time.Time  `json:"updatedAt" bson:"updated_at"`
 }
 
+// Flag reasons
+const (
+	FlagReasonRequestRateLimit   = "request_rate_limit"
+	FlagReasonVenueInfoRateLimit = "venue_info_rate_limit"
+)
+
 // CreateTableInput represents the data needed to create a table
 type CreateTableInput struct {
 	BranchID string `json:"branchId" binding:"required"`
 	Number   int    `json:"number" binding:"required,min=1"`
+	Label    string `json:"label,omitempty" binding:"max=40"`
+	Capacity int    `json:"capacity,omitempty" binding:"omitempty,min=1,max=100"`
+	Priority bool   `json:"priority,omitempty"`
+	ZoneID   string `json:"zoneId,omitempty"`
 }
 
 // UpdateTableInput represents the data needed to update a table
 type UpdateTableInput struct {
-	Number   int   `json:"number,omitempty" binding:"omitempty,min=1"`
-	IsActive *bool `json:"isActive,omitempty"`
+	Number int `json:"number,omitempty" binding:"omitempty,min=1"`
+	// Label and Capacity are cleared with an empty string and 0
+	Label    *string `json:"label,omitempty" binding:"omitempty,max=40"`
+	Capacity *int    `json:"capacity,omitempty" binding:"omitempty,min=0,max=100"`
+	IsActive *bool   `json:"isActive,omitempty"`
+	Priority *bool   `json:"priority,omitempty"`
+	// ZoneID moves the table to another zone of its branch; an empty string removes it from its zone
+	ZoneID *string `json:"zoneId,omitempty"`
+}
+
+// Table shapes on the floor plan
+const (
+	ShapeRound     = "round"
+	ShapeSquare    = "square"
+	ShapeRectangle = "rectangle"
+)
+
+// TableLayout is where a table sits on its branch's floor plan. Positions and
+// sizes are in the dashboard's plan units; rotation is in degrees clockwise.
+type TableLayout struct {
+	X        float64 `json:"x" bson:"x"`
+	Y        float64 `json:"y" bson:"y"`
+	Rotation float64 `json:"rotation" bson:"rotation"`
+	Shape    string  `json:"shape" bson:"shape"`
+	Width    float64 `json:"width" bson:"width"`
+	Height   float64 `json:"height" bson:"height"`
+}
+
+// FloorPlanInput replaces a branch's floor plan. Tables left out are removed from the plan.
+type FloorPlanInput struct {
+	Tables []TableLayoutInput `json:"tables" binding:"max=500,dive"`
+}
+
+// TableLayoutInput places one table on the floor plan
+type TableLayoutInput struct {
+	TableID  string  `json:"tableId" binding:"required,mongodb"`
+	X        float64 `json:"x" binding:"min=0,max=10000"`
+	Y        float64 `json:"y" binding:"min=0,max=10000"`
+	Rotation float64 `json:"rotation" binding:"min=0,lt=360"`
+	Shape    string  `json:"shape" binding:"required,oneof=round square rectangle"`
+	Width    float64 `json:"width" binding:"required,gt=0,max=10000"`
+	Height   float64 `json:"height" binding:"required,gt=0,max=10000"`
+}
+
+// BlockTableInput represents the data needed to temporarily block a table's public requests
+type BlockTableInput struct {
+	Minutes int `json:"minutes" binding:"required,min=1,max=10080"`
+}
+
+// QR image formats
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+// QRImageParams are the query options for a rendered table QR image
+type QRImageParams struct {
+	Size      int    `form:"size" binding:"omitempty,min=64,max=4096"`
+	ECC       string `form:"ecc" binding:"omitempty,oneof=L M Q H l m q h"`
+	QuietZone *int   `form:"quiet" binding:"omitempty,min=0,max=16"`
+	Logo      bool   `form:"logo"`
+}
+
+// QRSheetParams are the query options for a branch's printable QR sheet
+type QRSheetParams struct {
+	Paper           string `form:"paper" binding:"omitempty,oneof=a4 letter"`
+	Columns         int    `form:"columns" binding:"omitempty,min=1,max=6"`
+	Rows            int    `form:"rows" binding:"omitempty,min=1,max=8"`
+	ECC             string `form:"ecc" binding:"omitempty,oneof=L M Q H l m q h"`
+	IncludeInactive bool   `form:"includeInactive"`
+}
+
+// QRImage is a rendered table QR code or QR sheet
+type QRImage struct {
+	Data        []byte
+	ContentType string
+	Filename    string
+}
+
+// QRExportParams are the query options for a branch's ZIP export of QR images
+type QRExportParams struct {
+	Format string `form:"format" binding:"omitempty,oneof=png svg"`
+	QRImageParams
+}
+
+// QRExport is a ZIP archive of QR images written on demand, so large branches
+// are streamed to the client instead of being built in memory
+type QRExport struct {
+	Filename string
+	// Write renders the archive into w
+	Write func(w io.Writer) error
+}
+
+// NFCTag is the NDEF message to write on a table's NFC sticker.
+// Message holds a single URI record for URL and is base64 encoded in JSON.
+type NFCTag struct {
+	TableID     primitive.ObjectID `json:"tableId"`
+	TableNumber int                `json:"tableNumber"`
+	URL         string             `json:"url"`
+	Message     []byte             `json:"ndef"`
+}
+
+// NewNFCTag creates the NFC tag payload for a table, pointing at its signed request URL
+func NewNFCTag(table *Table) *NFCTag {
+	return &NFCTag{
+		TableID:     table.ID,
+		TableNumber: table.Number,
+		URL:         table.QRCode,
+		Message:     ndef.URIMessage(table.QRCode),
+	}
 }
 
 // BulkCreateTablesInput represents the data needed to create multiple tables
@@ -34,10 +186,80 @@ type BulkCreateTablesInput struct {
 	Count    int    `json:"count" binding:"required,min=1,max=100"`
 }
 
+// Bulk table actions
+const (
+	BulkActionActivate   = "activate"
+	BulkActionDeactivate = "deactivate"
+	BulkActionDelete     = "delete"
+	BulkActionRenumber   = "renumber"
+)
+
+// Per-table outcomes of a bulk action
+const (
+	BulkStatusUpdated   = "updated"
+	BulkStatusDeleted   = "deleted"
+	BulkStatusUnchanged = "unchanged"
+	BulkStatusFailed    = "failed"
+)
+
+// BulkTableActionInput applies one action to several tables of a branch, picked
+// either by ID or by an inclusive number range
+type BulkTableActionInput struct {
+	BranchID   string   `json:"branchId" binding:"required,mongodb"`
+	Action     string   `json:"action" binding:"required,oneof=activate deactivate delete renumber"`
+	TableIDs   []string `json:"tableIds,omitempty" binding:"omitempty,max=200,dive,mongodb"`
+	FromNumber int      `json:"fromNumber,omitempty" binding:"omitempty,min=1"`
+	ToNumber   int      `json:"toNumber,omitempty" binding:"omitempty,min=1"`
+	// Offset is added to each table number when renumbering; it can be negative
+	Offset int `json:"offset,omitempty" binding:"omitempty,min=-1000,max=1000"`
+}
+
+// BulkTableResult is the outcome of a bulk action on one table
+type BulkTableResult struct {
+	TableID   primitive.ObjectID `json:"tableId"`
+	Number    int                `json:"number"`
+	NewNumber int                `json:"newNumber,omitempty"`
+	Status    string             `json:"status"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// BulkTableActionResult reports what a bulk action did to each selected table
+type BulkTableActionResult struct {
+	Action    string            `json:"action"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BulkTableResult `json:"results"`
+}
+
+// IsPublicBlocked reports whether the table's public requests are blocked at the given time
+func (t *Table) IsPublicBlocked(now time.Time) bool {
+	return t.PublicBlockedUntil != nil && now.Before(*t.PublicBlockedUntil)
+}
+
+// DisplayName returns the name shown to guests and staff: the label if the
+// table has one, "Mesa <number>" otherwise
+func (t *Table) DisplayName() string {
+	if t.Label != "" {
+		return t.Label
+	}
+	return "Mesa " + strconv.Itoa(t.Number)
+}
+
+// ScanURL returns the URL encoded in the table's printed QR code: the short
+// link when the table has one, the full signed URL otherwise
+func (t *Table) ScanURL() string {
+	if t.ShortURL != "" {
+		return t.ShortURL
+	}
+	return t.QRCode
+}
+
 // NewTable creates a new table with the current timestamp
 func NewTable(branchID primitive.ObjectID, number int, qrCode string) *Table {
 	now := time.Now()
 	return &Table{
+		// The ID is assigned up front so batches of tables can be inserted in one round trip
+		ID:        primitive.NewObjectID(),
 		BranchID:  branchID,
 		Number:    number,
 		QRCode:    qrCode,
@@ -2,6 +2,9 @@ package handler
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 
 	"juansecalvinio/tepidolacuenta/internal/middleware"
@@ -58,6 +61,10 @@ func (h *Handler) Create(c *gin.Context) {
 
 	table, err := h.useCase.Create(c.Request.Context(), userID, input)
 	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
 		if errors.Is(err, pkg.ErrUnauthorized) {
 			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
 			return
@@ -130,6 +137,65 @@ func (h *Handler) BulkCreate(c *gin.Context) {
 	pkg.SuccessResponse(c, http.StatusCreated, "Tables created successfully", tables)
 }
 
+// BulkAction handles applying one action to several tables
+// @Summary Activate, deactivate, delete or renumber several tables
+// @Description Tables are picked by ID or by an inclusive number range. Each table is processed on its own and gets its own result.
+// @Tags tables
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.BulkTableActionInput true "Bulk action"
+// @Success 200 {object} pkg.Response{data=domain.BulkTableActionResult}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 403 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/tables/bulk/actions [post]
+func (h *Handler) BulkAction(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	var input domain.BulkTableActionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	result, err := h.useCase.BulkAction(c.Request.Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrPlanLimitReached) {
+			pkg.ForbiddenResponse(c, "Your plan does not allow more active tables", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to apply bulk action", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Bulk action applied", result)
+}
+
 // GetByID handles retrieving a table by ID
 // @Summary Get table by ID
 // @Tags tables
@@ -267,6 +333,7 @@ func extractRestaurantIDHint(c *gin.Context) *primitive.ObjectID {
 // @Success 200 {object} pkg.Response{data=domain.Table}
 // @Failure 400 {object} pkg.Response
 // @Failure 401 {object} pkg.Response
+// @Failure 403 {object} pkg.Response
 // @Failure 404 {object} pkg.Response
 // @Failure 500 {object} pkg.Response
 // @Router /api/v1/tables/{id} [put]
@@ -300,6 +367,10 @@ func (h *Handler) Update(c *gin.Context) {
 
 	table, err := h.useCase.Update(c.Request.Context(), tableID, userID, input)
 	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
 		if errors.Is(err, pkg.ErrNotFound) {
 			pkg.NotFoundResponse(c, "Table not found", err)
 			return
@@ -308,6 +379,10 @@ func (h *Handler) Update(c *gin.Context) {
 			pkg.UnauthorizedResponse(c, "You don't have access to this table", err)
 			return
 		}
+		if errors.Is(err, pkg.ErrPlanLimitReached) {
+			pkg.ForbiddenResponse(c, "Your plan does not allow more active tables", err)
+			return
+		}
 		pkg.InternalServerErrorResponse(c, "Failed to update table", err)
 		return
 	}
@@ -365,21 +440,753 @@ func (h *Handler) Delete(c *gin.Context) {
 	pkg.SuccessResponse(c, http.StatusOK, "Table deleted successfully", nil)
 }
 
-// RegisterRoutes registers all table routes.
-// Read routes are accessible by owners and employees; write routes are owner-only.
-func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
-	tables := router.Group("/tables")
-	{
-		tables.GET("/:id", h.GetByID)
-		tables.GET("/branch/:branchId", h.ListByBranch)
+// BlockPublicRequests handles blocking a table's public requests
+// @Summary Temporarily block public requests for a table
+// @Tags tables
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Table ID"
+// @Param input body domain.BlockTableInput true "Block duration"
+// @Success 200 {object} pkg.Response{data=domain.Table}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/tables/{id}/block [put]
+func (h *Handler) BlockPublicRequests(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	tableIDStr := c.Param("id")
+	tableID, err := primitive.ObjectIDFromHex(tableIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid table ID", err)
+		return
+	}
+
+	var input domain.BlockTableInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	table, err := h.useCase.BlockPublicRequests(c.Request.Context(), tableID, userID, input)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this table", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to block table", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Table blocked successfully", table)
+}
+
+// UnblockPublicRequests handles lifting a table's public request block
+// @Summary Unblock public requests for a table
+// @Tags tables
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Table ID"
+// @Success 200 {object} pkg.Response{data=domain.Table}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/tables/{id}/block [delete]
+func (h *Handler) UnblockPublicRequests(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	tableIDStr := c.Param("id")
+	tableID, err := primitive.ObjectIDFromHex(tableIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid table ID", err)
+		return
+	}
+
+	table, err := h.useCase.UnblockPublicRequests(c.Request.Context(), tableID, userID)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this table", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to unblock table", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Table unblocked successfully", table)
+}
+
+// ClearFlag handles clearing a table's abuse flag
+// @Summary Clear a table's abuse flag
+// @Tags tables
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Table ID"
+// @Success 200 {object} pkg.Response{data=domain.Table}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/tables/{id}/flag [delete]
+func (h *Handler) ClearFlag(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	tableIDStr := c.Param("id")
+	tableID, err := primitive.ObjectIDFromHex(tableIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid table ID", err)
+		return
+	}
+
+	table, err := h.useCase.ClearFlag(c.Request.Context(), tableID, userID)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this table", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to clear table flag", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Table flag cleared successfully", table)
+}
+
+// RotateQR handles re-issuing a table's QR code
+// @Summary Rotate a table's QR code, invalidating previously printed codes
+// @Tags tables
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Table ID"
+// @Success 200 {object} pkg.Response{data=domain.Table}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/tables/{id}/qr/rotate [post]
+func (h *Handler) RotateQR(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	tableIDStr := c.Param("id")
+	tableID, err := primitive.ObjectIDFromHex(tableIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid table ID", err)
+		return
+	}
+
+	table, err := h.useCase.RotateQR(c.Request.Context(), tableID, userID)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this table", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to rotate table QR code", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Table QR code rotated successfully", table)
+}
+
+// GetQRPNG handles rendering a table's QR code as PNG
+// @Summary Get table QR code as PNG
+// @Tags tables
+// @Produce png
+// @Security BearerAuth
+// @Param id path string true "Table ID"
+// @Param size query int false "Maximum width in pixels (64-4096, default 512)"
+// @Param ecc query string false "Error correction level: L, M (default), Q or H"
+// @Param quiet query int false "Quiet zone in modules (0-16, default 4)"
+// @Param logo query bool false "Draw the configured logo in the center (forces level H)"
+// @Success 200 {file} binary
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/tables/{id}/qr.png [get]
+func (h *Handler) GetQRPNG(c *gin.Context) {
+	h.renderQR(c, domain.QRFormatPNG)
+}
+
+// GetQRSVG handles rendering a table's QR code as SVG
+// @Summary Get table QR code as SVG
+// @Tags tables
+// @Produce image/svg+xml
+// @Security BearerAuth
+// @Param id path string true "Table ID"
+// @Param size query int false "Width in pixels (64-4096, default 512)"
+// @Param ecc query string false "Error correction level: L, M (default), Q or H"
+// @Param quiet query int false "Quiet zone in modules (0-16, default 4)"
+// @Param logo query bool false "Draw the configured logo in the center (forces level H)"
+// @Success 200 {file} binary
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/tables/{id}/qr.svg [get]
+func (h *Handler) GetQRSVG(c *gin.Context) {
+	h.renderQR(c, domain.QRFormatSVG)
+}
+
+func (h *Handler) renderQR(c *gin.Context, format string) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
 	}
 
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	tableIDStr := c.Param("id")
+	tableID, err := primitive.ObjectIDFromHex(tableIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid table ID", err)
+		return
+	}
+
+	var params domain.QRImageParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		pkg.BadRequestResponse(c, "Invalid query parameters", err)
+		return
+	}
+
+	qrImage, err := h.useCase.RenderQR(c.Request.Context(), tableID, userID, extractRestaurantIDHint(c), format, params)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this table", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this table", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to render QR code", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, qrImage.Filename))
+	c.Data(http.StatusOK, qrImage.ContentType, qrImage.Data)
+}
+
+// GetBranchQRSheet handles rendering a printable PDF with the QR codes of a branch's tables
+// @Summary Get printable QR sheet for a branch
+// @Tags tables
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Param paper query string false "Paper size: a4 (default) or letter"
+// @Param columns query int false "Columns per page (1-6, default 3)"
+// @Param rows query int false "Rows per page (1-8, default 4)"
+// @Param ecc query string false "Error correction level: L, M (default), Q or H"
+// @Param includeInactive query bool false "Include inactive tables"
+// @Success 200 {file} binary
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/branches/{id}/qr-sheet.pdf [get]
+func (h *Handler) GetBranchQRSheet(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchIDStr := c.Param("id")
+	branchID, err := primitive.ObjectIDFromHex(branchIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	var params domain.QRSheetParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		pkg.BadRequestResponse(c, "Invalid query parameters", err)
+		return
+	}
+
+	sheet, err := h.useCase.RenderBranchQRSheet(c.Request.Context(), branchID, userID, extractRestaurantIDHint(c), params)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to render QR sheet", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, sheet.Filename))
+	c.Data(http.StatusOK, sheet.ContentType, sheet.Data)
+}
+
+// GetBranchQRExport handles streaming a ZIP with the QR images of a branch's tables
+// @Summary Export a branch's table QR codes as a ZIP of images
+// @Tags tables
+// @Produce application/zip
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Param format query string false "Image format: png (default) or svg"
+// @Param size query int false "Width in pixels (64-4096, default 512)"
+// @Param ecc query string false "Error correction level: L, M (default), Q or H"
+// @Param quiet query int false "Quiet zone in modules (0-16, default 4)"
+// @Param logo query bool false "Draw the configured logo in the center (forces level H)"
+// @Success 200 {file} binary
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/branches/{id}/qr-export.zip [get]
+func (h *Handler) GetBranchQRExport(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchIDStr := c.Param("id")
+	branchID, err := primitive.ObjectIDFromHex(branchIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	var params domain.QRExportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		pkg.BadRequestResponse(c, "Invalid query parameters", err)
+		return
+	}
+
+	export, err := h.useCase.ExportBranchQRCodes(c.Request.Context(), branchID, userID, extractRestaurantIDHint(c), params)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to export QR codes", err)
+		return
+	}
+
+	// The archive is streamed, so a failure halfway can only cut the download short
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.Filename))
+	c.Status(http.StatusOK)
+	if err := export.Write(c.Writer); err != nil {
+		log.Printf("[GetBranchQRExport] error streaming export of branch %s: %v", branchIDStr, err)
+	}
+}
+
+// GetNFCTag handles returning the NDEF message to write on a table's NFC sticker
+// @Summary Get NFC tag payload for a table
+// @Description Returns a binary NDEF message with a single URI record pointing at the table's signed request URL
+// @Tags tables
+// @Produce application/octet-stream
+// @Security BearerAuth
+// @Param id path string true "Table ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/tables/{id}/nfc.ndef [get]
+func (h *Handler) GetNFCTag(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	tableIDStr := c.Param("id")
+	tableID, err := primitive.ObjectIDFromHex(tableIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid table ID", err)
+		return
+	}
+
+	tag, err := h.useCase.GetNFCTag(c.Request.Context(), tableID, userID, extractRestaurantIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Table not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this table", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this table", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to build NFC tag", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="mesa-%d.ndef"`, tag.TableNumber))
+	c.Data(http.StatusOK, "application/octet-stream", tag.Message)
+}
+
+// ListBranchNFCTags handles returning the NFC tag payloads of a branch's tables
+// @Summary List NFC tag payloads for a branch
+// @Description Returns the base64 NDEF message of every active table in the branch, ordered by number
+// @Tags tables
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Success 200 {object} pkg.Response{data=[]domain.NFCTag}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/branches/{id}/nfc [get]
+func (h *Handler) ListBranchNFCTags(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchIDStr := c.Param("id")
+	branchID, err := primitive.ObjectIDFromHex(branchIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	tags, err := h.useCase.ListBranchNFCTags(c.Request.Context(), branchID, userID, extractRestaurantIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to build NFC tags", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "NFC tags retrieved successfully", tags)
+}
+
+// SaveFloorPlan handles replacing a branch's floor plan
+// @Summary Save the floor plan of a branch
+// @Description Places the listed tables on the branch floor plan. Tables left out of the list are removed from the plan.
+// @Tags tables
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Param input body domain.FloorPlanInput true "Table layouts"
+// @Success 200 {object} pkg.Response{data=[]domain.Table}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/branches/{id}/floor-plan [put]
+func (h *Handler) SaveFloorPlan(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	var input domain.FloorPlanInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	tables, err := h.useCase.SaveFloorPlan(c.Request.Context(), branchID, userID, input)
+	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to save floor plan", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Floor plan saved successfully", tables)
+}
+
+// maxImportSize is the largest CSV accepted by the table import
+const maxImportSize = 1 << 20
+
+// ImportTables handles creating and updating a branch's tables from a CSV
+// @Summary Import the tables of a branch from a CSV
+// @Description Creates or updates tables from a CSV with a header row and the columns number (required), label, zone, capacity and active, matched to existing tables by number. The file can be sent as the multipart field "file" or as the raw request body. Nothing is written unless every row is valid; invalid files return the row-level report with a 422. With dryRun=true the file is only validated.
+// @Tags tables
+// @Accept multipart/form-data,text/csv
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Branch ID"
+// @Param file formData file false "CSV file"
+// @Param dryRun query bool false "Validate without writing"
+// @Success 200 {object} pkg.Response{data=domain.TableImportReport}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 403 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 422 {object} pkg.Response{data=domain.TableImportReport}
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/branches/{id}/tables/import [post]
+func (h *Handler) ImportTables(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	var params domain.TableImportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		pkg.BadRequestResponse(c, "Invalid parameters", err)
+		return
+	}
+
+	var body io.Reader
+	if c.ContentType() == "multipart/form-data" {
+		// Multipart uploads carry some overhead besides the file itself
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize+64<<10)
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			pkg.BadRequestResponse(c, "The CSV must be sent in the file field", err)
+			return
+		}
+		if fileHeader.Size > maxImportSize {
+			pkg.ErrorResponse(c, http.StatusRequestEntityTooLarge, "The CSV can't be larger than 1 MB", nil)
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			pkg.BadRequestResponse(c, "Failed to read the CSV", err)
+			return
+		}
+		defer file.Close()
+		body = file
+	} else {
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	}
+
+	report, err := h.useCase.ImportTables(c.Request.Context(), branchID, userID, body, params)
+	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrPlanLimitReached) {
+			pkg.ForbiddenResponse(c, "Your plan does not allow more tables", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to import tables", err)
+		return
+	}
+
+	if !report.Valid {
+		c.JSON(http.StatusUnprocessableEntity, pkg.Response{
+			Success: false,
+			Message: "The CSV has invalid rows",
+			Data:    report,
+		})
+		return
+	}
+	if params.DryRun {
+		pkg.SuccessResponse(c, http.StatusOK, "CSV validated successfully", report)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Tables imported successfully", report)
+}
+
+// RegisterRoutes registers all table routes.
+// Read routes are accessible by owners and employees; write routes are owner-only.
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	tables := router.Group("/tables")
+	{
+		tables.GET("/:id", h.GetByID)
+		tables.GET("/:id/qr.png", h.GetQRPNG)
+		tables.GET("/:id/qr.svg", h.GetQRSVG)
+		tables.GET("/:id/nfc.ndef", h.GetNFCTag)
+		tables.GET("/branch/:branchId", h.ListByBranch)
+	}
+
+	// The QR sheet, export, NFC tags and floor plan live under /branches but are built from the branch's tables
+	router.GET("/branches/:id/qr-sheet.pdf", h.GetBranchQRSheet)
+	router.GET("/branches/:id/qr-export.zip", h.GetBranchQRExport)
+	router.GET("/branches/:id/nfc", h.ListBranchNFCTags)
+	router.PUT("/branches/:id/floor-plan", middleware.OwnerOnly(), h.SaveFloorPlan)
+	router.POST("/branches/:id/tables/import", middleware.OwnerOnly(), h.ImportTables)
+
 	ownerTables := tables.Group("")
 	ownerTables.Use(middleware.OwnerOnly())
 	{
 		ownerTables.POST("", h.Create)
 		ownerTables.POST("/bulk", h.BulkCreate)
+		ownerTables.POST("/bulk/actions", h.BulkAction)
 		ownerTables.PUT("/:id", h.Update)
 		ownerTables.DELETE("/:id", h.Delete)
+		ownerTables.PUT("/:id/block", h.BlockPublicRequests)
+		ownerTables.DELETE("/:id/block", h.UnblockPublicRequests)
+		ownerTables.DELETE("/:id/flag", h.ClearFlag)
+		ownerTables.POST("/:id/qr/rotate", h.RotateQR)
 	}
 }
@@ -37,6 +37,19 @@ func (r *mongoRepository) Create(ctx context.Context, table *domain.Table) error
 	return nil
 }
 
+func (r *mongoRepository) CreateMany(ctx context.Context, tables []*domain.Table) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	docs := make([]interface{}, len(tables))
+	for i, table := range tables {
+		docs[i] = table
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
 func (r *mongoRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Table, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -92,6 +105,60 @@ func (r *mongoRepository) FindByBranchAndNumber(ctx context.Context, branchID pr
 	return &table, nil
 }
 
+func (r *mongoRepository) FindByBranchAndLabel(ctx context.Context, branchID primitive.ObjectID, label string) (*domain.Table, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var table domain.Table
+	err := r.collection.FindOne(ctx, bson.M{
+		"branch_id": branchID,
+		"label":     label,
+	}).Decode(&table)
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &table, nil
+}
+
+func (r *mongoRepository) FindByToken(ctx context.Context, token string) (*domain.Table, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var table domain.Table
+	err := r.collection.FindOne(ctx, bson.M{"token": token}).Decode(&table)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, pkg.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &table, nil
+}
+
+func (r *mongoRepository) FindMergedInto(ctx context.Context, targetTableID primitive.ObjectID) ([]*domain.Table, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"merged_into_table_id": targetTableID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tables := make([]*domain.Table, 0)
+	if err := cursor.All(ctx, &tables); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
 func (r *mongoRepository) Update(ctx context.Context, table *domain.Table) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -100,10 +167,18 @@ func (r *mongoRepository) Update(ctx context.Context, table *domain.Table) error
 
 	update := bson.M{
 		"$set": bson.M{
-			"number":     table.Number,
-			"qr_code":    table.QRCode,
-			"is_active":  table.IsActive,
-			"updated_at": table.UpdatedAt,
+			"number":               table.Number,
+			"label":                table.Label,
+			"capacity":             table.Capacity,
+			"qr_code":              table.QRCode,
+			"qr_version":           table.QRVersion,
+			"slug":                 table.Slug,
+			"short_url":            table.ShortURL,
+			"is_active":            table.IsActive,
+			"priority":             table.Priority,
+			"zone_id":              table.ZoneID,
+			"merged_into_table_id": table.MergedIntoTableID,
+			"updated_at":           table.UpdatedAt,
 		},
 	}
 
@@ -119,6 +194,133 @@ func (r *mongoRepository) Update(ctx context.Context, table *domain.Table) error
 	return nil
 }
 
+func (r *mongoRepository) Flag(ctx context.Context, id primitive.ObjectID, reason string, at time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "flagged_at": bson.M{"$exists": false}}
+	update := bson.M{
+		"$set": bson.M{
+			"flagged_at":  at,
+			"flag_reason": reason,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *mongoRepository) ClearFlag(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$unset": bson.M{"flagged_at": "", "flag_reason": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return pkg.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *mongoRepository) SetPublicBlockedUntil(ctx context.Context, id primitive.ObjectID, until *time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	set := bson.M{"updated_at": time.Now()}
+	update := bson.M{"$set": set}
+	if until != nil {
+		set["public_blocked_until"] = *until
+	} else {
+		update["$unset"] = bson.M{"public_blocked_until": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return pkg.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *mongoRepository) ReplaceLayouts(ctx context.Context, branchID primitive.ObjectID, layouts map[primitive.ObjectID]domain.TableLayout) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	placed := make([]primitive.ObjectID, 0, len(layouts))
+	models := make([]mongo.WriteModel, 0, len(layouts)+1)
+	for id, layout := range layouts {
+		placed = append(placed, id)
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "branch_id": branchID}).
+			SetUpdate(bson.M{"$set": bson.M{"layout": layout, "updated_at": now}}))
+	}
+	models = append(models, mongo.NewUpdateManyModel().
+		SetFilter(bson.M{"branch_id": branchID, "_id": bson.M{"$nin": placed}, "layout": bson.M{"$exists": true}}).
+		SetUpdate(bson.M{"$unset": bson.M{"layout": ""}, "$set": bson.M{"updated_at": now}}))
+
+	_, err := r.collection.BulkWrite(ctx, models)
+	return err
+}
+
+func (r *mongoRepository) SetActive(ctx context.Context, ids []primitive.ObjectID, active bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$set": bson.M{
+			"is_active":  active,
+			"updated_at": time.Now(),
+		},
+	}
+
+	_, err := r.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	return err
+}
+
+func (r *mongoRepository) ClearMergedInto(ctx context.Context, targetTableID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$unset": bson.M{"merged_into_table_id": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+
+	_, err := r.collection.UpdateMany(ctx, bson.M{"merged_into_table_id": targetTableID}, update)
+	return err
+}
+
+func (r *mongoRepository) ClearZone(ctx context.Context, zoneID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$unset": bson.M{"zone_id": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+
+	_, err := r.collection.UpdateMany(ctx, bson.M{"zone_id": zoneID}, update)
+	return err
+}
+
 func (r *mongoRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
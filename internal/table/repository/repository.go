@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"juansecalvinio/tepidolacuenta/internal/table/domain"
 
@@ -11,9 +12,43 @@ import (
 // Repository defines the interface for table persistence operations
 type Repository interface {
 	Create(ctx context.Context, table *domain.Table) error
+	// CreateMany inserts tables whose IDs were already assigned in a single round trip
+	CreateMany(ctx context.Context, tables []*domain.Table) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Table, error)
 	FindByBranchID(ctx context.Context, branchID primitive.ObjectID) ([]*domain.Table, error)
 	FindByBranchAndNumber(ctx context.Context, branchID primitive.ObjectID, number int) (*domain.Table, error)
+	// FindByBranchAndLabel returns nil if no table of the branch has the label
+	FindByBranchAndLabel(ctx context.Context, branchID primitive.ObjectID, label string) (*domain.Table, error)
+	// FindByToken finds a table by the token printed in its QR code
+	FindByToken(ctx context.Context, token string) (*domain.Table, error)
+	FindMergedInto(ctx context.Context, targetTableID primitive.ObjectID) ([]*domain.Table, error)
 	Update(ctx context.Context, table *domain.Table) error
+	// Flag marks the table as flagged unless it already is. Reports whether it was newly flagged.
+	Flag(ctx context.Context, id primitive.ObjectID, reason string, at time.Time) (bool, error)
+	ClearFlag(ctx context.Context, id primitive.ObjectID) error
+	// SetPublicBlockedUntil blocks public requests until the given time, or unblocks them when nil
+	SetPublicBlockedUntil(ctx context.Context, id primitive.ObjectID, until *time.Time) error
+	// ReplaceLayouts sets the floor plan layout of the given tables of a branch
+	// and removes every other table of the branch from the plan
+	ReplaceLayouts(ctx context.Context, branchID primitive.ObjectID, layouts map[primitive.ObjectID]domain.TableLayout) error
+	// SetActive activates or deactivates the given tables
+	SetActive(ctx context.Context, ids []primitive.ObjectID, active bool) error
+	// ClearMergedInto unmerges every table merged into the given target table
+	ClearMergedInto(ctx context.Context, targetTableID primitive.ObjectID) error
+	// ClearZone removes every table from the given zone
+	ClearZone(ctx context.Context, zoneID primitive.ObjectID) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 }
+
+// ShortLinkRepository defines the interface for table short link persistence
+type ShortLinkRepository interface {
+	// Create stores the link. If its slug is already taken, a new one is drawn
+	// from newSlug and the insert retried a few times.
+	Create(ctx context.Context, link *domain.ShortLink, newSlug func() string) error
+	// CreateMany inserts links in a single round trip. Slug collisions are not
+	// retried; the insert fails and the caller can try again with new slugs.
+	CreateMany(ctx context.Context, links []*domain.ShortLink) error
+	FindBySlug(ctx context.Context, slug string) (*domain.ShortLink, error)
+	// RecordScan bumps the link's scan count and last scan time
+	RecordScan(ctx context.Context, id primitive.ObjectID, at time.Time) error
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/table/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// slugAttempts is how many slugs are tried before giving up on a collision.
+// With 62^8 possible slugs a single retry is already very unlikely.
+const slugAttempts = 3
+
+type mongoShortLinkRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoShortLinkRepository creates a new MongoDB repository for table short links
+func NewMongoShortLinkRepository(db *mongo.Database) ShortLinkRepository {
+	return &mongoShortLinkRepository{
+		collection: db.Collection("table_short_links"),
+	}
+}
+
+func (r *mongoShortLinkRepository) Create(ctx context.Context, link *domain.ShortLink, newSlug func() string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		result, err := r.collection.InsertOne(ctx, link)
+		if err == nil {
+			link.ID = result.InsertedID.(primitive.ObjectID)
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) || attempt == slugAttempts {
+			return err
+		}
+		link.Slug = newSlug()
+	}
+}
+
+func (r *mongoShortLinkRepository) CreateMany(ctx context.Context, links []*domain.ShortLink) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	docs := make([]interface{}, len(links))
+	for i, link := range links {
+		docs[i] = link
+	}
+
+	result, err := r.collection.InsertMany(ctx, docs)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range result.InsertedIDs {
+		links[i].ID = id.(primitive.ObjectID)
+	}
+	return nil
+}
+
+func (r *mongoShortLinkRepository) FindBySlug(ctx context.Context, slug string) (*domain.ShortLink, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var link domain.ShortLink
+	err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&link)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, pkg.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+func (r *mongoShortLinkRepository) RecordScan(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$inc": bson.M{"scan_count": 1},
+		"$set": bson.M{"last_scanned_at": at},
+	})
+	return err
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	subscriptionDomain "juansecalvinio/tepidolacuenta/internal/subscription/domain"
+	"juansecalvinio/tepidolacuenta/internal/table/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BulkAction applies one action to several tables of a branch and reports the
+// outcome per table. Activating and deactivating is a single write, so either
+// every selected table changes or none does; the other actions process tables
+// one at a time, so a failure on one table doesn't stop the rest.
+func (uc *tableUseCase) BulkAction(ctx context.Context, userID primitive.ObjectID, input domain.BulkTableActionInput) (*domain.BulkTableActionResult, error) {
+	branchID, err := primitive.ObjectIDFromHex(input.BranchID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid branch ID", pkg.ErrInvalidInput)
+	}
+
+	restaurantID, err := uc.verifyBranchAccess(ctx, branchID, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Action == domain.BulkActionRenumber && input.Offset == 0 {
+		return nil, fmt.Errorf("%w: offset is required to renumber tables", pkg.ErrInvalidInput)
+	}
+
+	tables, err := uc.repo.FindByBranchID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.BulkTableActionResult{
+		Action:  input.Action,
+		Results: make([]domain.BulkTableResult, 0),
+	}
+
+	selected, err := selectBulkTables(tables, input, result)
+	if err != nil {
+		return nil, err
+	}
+
+	switch input.Action {
+	case domain.BulkActionActivate, domain.BulkActionDeactivate:
+		active := input.Action == domain.BulkActionActivate
+		changed := make([]*domain.Table, 0, len(selected))
+		ids := make([]primitive.ObjectID, 0, len(selected))
+		for _, table := range selected {
+			if table.IsActive == active {
+				addBulkResult(result, table, domain.BulkStatusUnchanged, nil)
+				continue
+			}
+			changed = append(changed, table)
+			ids = append(ids, table.ID)
+		}
+		if len(changed) == 0 {
+			break
+		}
+
+		// The tables are flipped in a single write, so they all change or none does
+		setActive := func(ctx context.Context) error {
+			return uc.repo.SetActive(ctx, ids, active)
+		}
+		if active {
+			err = uc.activateTables(ctx, *restaurantID, branchID, ids, setActive)
+		} else {
+			err = setActive(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range changed {
+			table.IsActive = active
+			addBulkResult(result, table, domain.BulkStatusUpdated, nil)
+		}
+
+	case domain.BulkActionDelete:
+		for _, table := range selected {
+			addBulkResult(result, table, domain.BulkStatusDeleted, uc.repo.Delete(ctx, table.ID))
+		}
+
+	case domain.BulkActionRenumber:
+		uc.renumberTables(ctx, tables, selected, input.Offset, result)
+	}
+
+	return result, nil
+}
+
+// selectBulkTables picks the tables of a bulk action, by ID or by number range,
+// ordered by number. IDs that don't belong to the branch are reported as failed.
+func selectBulkTables(tables []*domain.Table, input domain.BulkTableActionInput, result *domain.BulkTableActionResult) ([]*domain.Table, error) {
+	byRange := input.FromNumber != 0 || input.ToNumber != 0
+	if len(input.TableIDs) > 0 && byRange {
+		return nil, fmt.Errorf("%w: pick tables either by ID or by number range, not both", pkg.ErrInvalidInput)
+	}
+
+	selected := make([]*domain.Table, 0)
+	switch {
+	case byRange:
+		if input.FromNumber == 0 || input.ToNumber == 0 || input.FromNumber > input.ToNumber {
+			return nil, fmt.Errorf("%w: fromNumber and toNumber must form a valid range", pkg.ErrInvalidInput)
+		}
+		for _, table := range tables {
+			if table.Number >= input.FromNumber && table.Number <= input.ToNumber {
+				selected = append(selected, table)
+			}
+		}
+
+	case len(input.TableIDs) > 0:
+		byID := make(map[primitive.ObjectID]*domain.Table, len(tables))
+		for _, table := range tables {
+			byID[table.ID] = table
+		}
+		seen := make(map[primitive.ObjectID]bool)
+		for _, idStr := range input.TableIDs {
+			id, _ := primitive.ObjectIDFromHex(idStr)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			table, ok := byID[id]
+			if !ok {
+				result.Results = append(result.Results, domain.BulkTableResult{
+					TableID: id,
+					Status:  domain.BulkStatusFailed,
+					Error:   "table not found in this branch",
+				})
+				result.Failed++
+				continue
+			}
+			selected = append(selected, table)
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: tableIds or a fromNumber/toNumber range is required", pkg.ErrInvalidInput)
+	}
+
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Number < selected[j].Number })
+	return selected, nil
+}
+
+// activateTables runs write, which activates the given tables of a branch, in a
+// transaction with the plan limit check. The branch is touched first so concurrent
+// activations in the same branch conflict and the retried one counts the other's tables.
+func (uc *tableUseCase) activateTables(ctx context.Context, restaurantID, branchID primitive.ObjectID, ids []primitive.ObjectID, write func(ctx context.Context) error) error {
+	return uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.branchRepo.Touch(ctx, branchID); err != nil {
+			return err
+		}
+
+		tables, err := uc.repo.FindByBranchID(ctx, branchID)
+		if err != nil {
+			return err
+		}
+
+		activating := make(map[primitive.ObjectID]bool, len(ids))
+		for _, id := range ids {
+			activating[id] = true
+		}
+		selected := make([]*domain.Table, 0, len(ids))
+		for _, table := range tables {
+			if activating[table.ID] {
+				selected = append(selected, table)
+			}
+		}
+
+		if err := uc.checkActivationPlanLimit(ctx, restaurantID, tables, selected); err != nil {
+			return err
+		}
+		return write(ctx)
+	})
+}
+
+// checkActivationPlanLimit verifies the branch stays within the plan's table
+// limit once the selected tables are active. Tables can be over the limit after
+// a plan downgrade, as long as the extra ones stay inactive.
+func (uc *tableUseCase) checkActivationPlanLimit(ctx context.Context, restaurantID primitive.ObjectID, tables, selected []*domain.Table) error {
+	active := 0
+	for _, table := range tables {
+		if table.IsActive {
+			active++
+		}
+	}
+	for _, table := range selected {
+		if !table.IsActive {
+			active++
+		}
+	}
+
+	return uc.checkActiveTablesLimit(ctx, restaurantID, active)
+}
+
+// checkActiveTablesLimit verifies the plan allows a branch to have `active` active tables
+func (uc *tableUseCase) checkActiveTablesLimit(ctx context.Context, restaurantID primitive.ObjectID, active int) error {
+	maxTables, err := uc.planMaxTables(ctx, restaurantID)
+	if err != nil {
+		return err
+	}
+	if maxTables != subscriptionDomain.Unlimited && active > maxTables {
+		return pkg.ErrPlanLimitReached
+	}
+	return nil
+}
+
+// renumberTables shifts the number of the selected tables by offset. Tables are
+// moved in the direction of the shift so a range can slide over its own numbers;
+// a table whose new number is taken by a table left in place fails.
+func (uc *tableUseCase) renumberTables(ctx context.Context, tables, selected []*domain.Table, offset int, result *domain.BulkTableActionResult) {
+	byNumber := make(map[int]*domain.Table, len(tables))
+	for _, table := range tables {
+		byNumber[table.Number] = table
+	}
+
+	if offset > 0 {
+		sort.Slice(selected, func(i, j int) bool { return selected[i].Number > selected[j].Number })
+	}
+
+	for _, table := range selected {
+		from := table.Number
+		to := from + offset
+		if !pkg.IsValidTableNumber(to) {
+			addBulkResult(result, table, domain.BulkStatusFailed, fmt.Errorf("table number %d must be greater than 0", to))
+			continue
+		}
+		if other, ok := byNumber[to]; ok && other.ID != table.ID {
+			addBulkResult(result, table, domain.BulkStatusFailed, fmt.Errorf("table number %d already exists for this branch", to))
+			continue
+		}
+
+		table.Number = to
+		if err := uc.repo.Update(ctx, table); err != nil {
+			table.Number = from
+			addBulkResult(result, table, domain.BulkStatusFailed, err)
+			continue
+		}
+		delete(byNumber, from)
+		byNumber[to] = table
+
+		result.Results = append(result.Results, domain.BulkTableResult{
+			TableID:   table.ID,
+			Number:    from,
+			NewNumber: to,
+			Status:    domain.BulkStatusUpdated,
+		})
+		result.Succeeded++
+	}
+}
+
+// addBulkResult records the outcome of a bulk action on a table; a non-nil err marks it failed
+func addBulkResult(result *domain.BulkTableActionResult, table *domain.Table, status string, err error) {
+	entry := domain.BulkTableResult{TableID: table.ID, Number: table.Number, Status: status}
+	if err != nil {
+		entry.Status = domain.BulkStatusFailed
+		entry.Error = err.Error()
+		result.Failed++
+	} else {
+		result.Succeeded++
+	}
+	result.Results = append(result.Results, entry)
+}
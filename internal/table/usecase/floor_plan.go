@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/table/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SaveFloorPlan replaces the floor plan of a branch and returns its tables ordered by number.
+// Tables not listed in the input are taken off the plan.
+func (uc *tableUseCase) SaveFloorPlan(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, input domain.FloorPlanInput) ([]*domain.Table, error) {
+	if _, err := uc.verifyBranchAccess(ctx, branchID, userID, nil); err != nil {
+		return nil, err
+	}
+
+	tables, err := uc.repo.FindByBranchID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*domain.Table, len(tables))
+	for _, table := range tables {
+		byID[table.ID] = table
+	}
+
+	layouts := make(map[primitive.ObjectID]domain.TableLayout, len(input.Tables))
+	for _, item := range input.Tables {
+		tableID, err := primitive.ObjectIDFromHex(item.TableID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid table ID %q", pkg.ErrInvalidInput, item.TableID)
+		}
+		if _, ok := byID[tableID]; !ok {
+			return nil, fmt.Errorf("%w: table %s does not belong to this branch", pkg.ErrInvalidInput, item.TableID)
+		}
+		if _, dup := layouts[tableID]; dup {
+			return nil, fmt.Errorf("%w: table %s is placed more than once", pkg.ErrInvalidInput, item.TableID)
+		}
+		layouts[tableID] = domain.TableLayout{
+			X:        item.X,
+			Y:        item.Y,
+			Rotation: item.Rotation,
+			Shape:    item.Shape,
+			Width:    item.Width,
+			Height:   item.Height,
+		}
+	}
+
+	if err := uc.repo.ReplaceLayouts(ctx, branchID, layouts); err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		table.Layout = nil
+		if layout, ok := layouts[table.ID]; ok {
+			table.Layout = &layout
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Number < tables[j].Number })
+
+	return tables, nil
+}
@@ -0,0 +1,398 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/table/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// importColumns maps the accepted CSV headers, in English or Spanish, to their column
+var importColumns = map[string]string{
+	"number":    "number",
+	"numero":    "number",
+	"número":    "number",
+	"label":     "label",
+	"etiqueta":  "label",
+	"zone":      "zone",
+	"zona":      "zone",
+	"capacity":  "capacity",
+	"capacidad": "capacity",
+	"active":    "active",
+	"activa":    "active",
+}
+
+// importRow is a parsed CSV row. Fields are nil when their column is missing from the file.
+type importRow struct {
+	report   *domain.TableImportRow
+	number   int
+	label    *string
+	zoneID   **primitive.ObjectID
+	capacity *int
+	active   *bool
+}
+
+// ImportTables creates or updates a branch's tables from a CSV with a header row
+// and the columns number (required), label, zone, capacity and active. Rows are
+// matched to existing tables by number. Every row is validated first; only when
+// all of them are valid, and unless it's a dry run, the changes are written in a
+// single transaction.
+func (uc *tableUseCase) ImportTables(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, data io.Reader, params domain.TableImportParams) (*domain.TableImportReport, error) {
+	restaurantID, err := uc.verifyBranchAccess(ctx, branchID, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	header, records, lines, err := readImportCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := uc.zoneRepo.FindByBranchID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	zoneIDs := make(map[string]primitive.ObjectID, len(zones))
+	for _, zone := range zones {
+		zoneIDs[strings.ToLower(zone.Name)] = zone.ID
+	}
+
+	tables, err := uc.repo.FindByBranchID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	byNumber := make(map[int]*domain.Table, len(tables))
+	for _, table := range tables {
+		byNumber[table.Number] = table
+	}
+
+	report := &domain.TableImportReport{
+		DryRun: params.DryRun,
+		Rows:   make([]domain.TableImportRow, len(records)),
+	}
+
+	// Parse every row, then apply the valid ones to the tables in memory
+	rows := make([]*importRow, 0, len(records))
+	seenNumbers := make(map[int]int)
+	for i, record := range records {
+		report.Rows[i] = domain.TableImportRow{Line: lines[i]}
+		row := parseImportRow(header, record, zoneIDs, &report.Rows[i])
+		if row.number != 0 {
+			if line, dup := seenNumbers[row.number]; dup {
+				row.fail("number %d is already used on line %d", row.number, line)
+			} else {
+				seenNumbers[row.number] = row.report.Line
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	creates := make([]*domain.Table, 0)
+	updates := make([]*domain.Table, 0)
+	activated := 0
+	rowByNumber := make(map[int]*importRow)
+	for _, row := range rows {
+		if len(row.report.Errors) > 0 {
+			continue
+		}
+		rowByNumber[row.number] = row
+
+		table, exists := byNumber[row.number]
+		if exists {
+			row.report.Action = domain.ImportActionUpdate
+			updates = append(updates, table)
+		} else {
+			row.report.Action = domain.ImportActionCreate
+			table = domain.NewTableWithQR(uc.qrService, branchID, row.number)
+			byNumber[row.number] = table
+			creates = append(creates, table)
+		}
+		wasActive := exists && table.IsActive
+		row.applyTo(table)
+		if table.IsActive && !wasActive {
+			activated++
+		}
+	}
+
+	// Labels must stay unique across the branch once the import is applied
+	labelNumbers := make(map[string][]int)
+	for number, table := range byNumber {
+		if table.Label != "" {
+			labelNumbers[table.Label] = append(labelNumbers[table.Label], number)
+		}
+	}
+	for label, numbers := range labelNumbers {
+		if len(numbers) < 2 {
+			continue
+		}
+		for _, number := range numbers {
+			if row, ok := rowByNumber[number]; ok {
+				row.fail("label %q is used by more than one table", label)
+			}
+		}
+	}
+
+	report.Valid = true
+	for _, row := range rows {
+		if len(row.report.Errors) > 0 {
+			report.Valid = false
+			row.report.Action = ""
+		}
+	}
+	if !report.Valid {
+		return report, nil
+	}
+	report.Created = len(creates)
+	report.Updated = len(updates)
+
+	if len(creates) > 0 {
+		if err := uc.checkTablePlanLimit(ctx, *restaurantID, branchID, len(creates)); err != nil {
+			return nil, err
+		}
+	}
+	// Tables switched on by the import, new or existing, must fit in the plan's active limit
+	if activated > 0 {
+		active := 0
+		for _, table := range byNumber {
+			if table.IsActive {
+				active++
+			}
+		}
+		if err := uc.checkActiveTablesLimit(ctx, *restaurantID, active); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.DryRun {
+		return report, nil
+	}
+
+	links := make([]*domain.ShortLink, 0, len(creates))
+	for _, table := range creates {
+		links = append(links, domain.NewTableShortLink(uc.qrService, table))
+	}
+
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Recount against the stored tables, touching the branch so a concurrent
+		// activation conflicts with the import instead of both passing the check
+		if activated > 0 {
+			if err := uc.branchRepo.Touch(ctx, branchID); err != nil {
+				return err
+			}
+			current, err := uc.repo.FindByBranchID(ctx, branchID)
+			if err != nil {
+				return err
+			}
+			if err := uc.checkActiveTablesLimit(ctx, *restaurantID, activeAfterImport(current, creates, updates)); err != nil {
+				return err
+			}
+		}
+		if len(creates) > 0 {
+			if err := uc.shortLinkRepo.CreateMany(ctx, links); err != nil {
+				return fmt.Errorf("failed to create short links: %w", err)
+			}
+			if err := uc.repo.CreateMany(ctx, creates); err != nil {
+				return fmt.Errorf("failed to create tables: %w", err)
+			}
+		}
+		for _, table := range updates {
+			if err := uc.repo.Update(ctx, table); err != nil {
+				return fmt.Errorf("failed to update table %d: %w", table.Number, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// activeAfterImport counts the active tables of a branch once the imported
+// tables replace the stored ones with the same number
+func activeAfterImport(current, creates, updates []*domain.Table) int {
+	byNumber := make(map[int]bool, len(current)+len(creates))
+	for _, table := range current {
+		byNumber[table.Number] = table.IsActive
+	}
+	for _, table := range creates {
+		byNumber[table.Number] = table.IsActive
+	}
+	for _, table := range updates {
+		byNumber[table.Number] = table.IsActive
+	}
+
+	active := 0
+	for _, isActive := range byNumber {
+		if isActive {
+			active++
+		}
+	}
+	return active
+}
+
+// readImportCSV reads the header and records of an import file. Semicolon
+// separated files, as exported by spreadsheets in Spanish locales, are detected
+// from the header line. The line each record starts on is returned alongside it.
+func readImportCSV(data io.Reader) ([]string, [][]string, []int, error) {
+	raw, err := io.ReadAll(data)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: failed to read the CSV: %v", pkg.ErrInvalidInput, err)
+	}
+	raw = bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(raw))
+	reader.TrimLeadingSpace = true
+	firstLine, _, _ := bytes.Cut(raw, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	records := make([][]string, 0)
+	lines := make([]int, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, nil, nil, fmt.Errorf("%w: invalid CSV on line %d: %v", pkg.ErrInvalidInput, parseErr.Line, parseErr.Err)
+			}
+			return nil, nil, nil, fmt.Errorf("%w: invalid CSV: %v", pkg.ErrInvalidInput, err)
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	if len(records) < 2 {
+		return nil, nil, nil, fmt.Errorf("%w: the CSV needs a header row and at least one table", pkg.ErrInvalidInput)
+	}
+	if len(records)-1 > domain.MaxImportRows {
+		return nil, nil, nil, fmt.Errorf("%w: the CSV can't have more than %d tables", pkg.ErrInvalidInput, domain.MaxImportRows)
+	}
+
+	header := make([]string, len(records[0]))
+	seen := make(map[string]bool)
+	for i, name := range records[0] {
+		column, ok := importColumns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("%w: unknown column %q, expected number, label, zone, capacity and active", pkg.ErrInvalidInput, name)
+		}
+		if seen[column] {
+			return nil, nil, nil, fmt.Errorf("%w: column %q appears more than once", pkg.ErrInvalidInput, column)
+		}
+		seen[column] = true
+		header[i] = column
+	}
+	if !seen["number"] {
+		return nil, nil, nil, fmt.Errorf("%w: the number column is required", pkg.ErrInvalidInput)
+	}
+
+	return header, records[1:], lines[1:], nil
+}
+
+// parseImportRow validates a CSV record, collecting every problem in report.
+// Empty label, zone and capacity cells clear the field; an empty active cell means active.
+func parseImportRow(header, record []string, zoneIDs map[string]primitive.ObjectID, report *domain.TableImportRow) *importRow {
+	row := &importRow{report: report}
+
+	for i, column := range header {
+		value := strings.TrimSpace(record[i])
+
+		switch column {
+		case "number":
+			number, err := strconv.Atoi(value)
+			if err != nil || !pkg.IsValidTableNumber(number) {
+				row.fail("number must be a whole number greater than 0")
+				continue
+			}
+			row.number = number
+			report.Number = number
+
+		case "label":
+			if len([]rune(value)) > 40 {
+				row.fail("label can't be longer than 40 characters")
+				continue
+			}
+			row.label = &value
+
+		case "zone":
+			var zoneID *primitive.ObjectID
+			if value != "" {
+				id, ok := zoneIDs[strings.ToLower(value)]
+				if !ok {
+					row.fail("zone %q doesn't exist in this branch", value)
+					continue
+				}
+				zoneID = &id
+			}
+			row.zoneID = &zoneID
+
+		case "capacity":
+			capacity := 0
+			if value != "" {
+				var err error
+				capacity, err = strconv.Atoi(value)
+				if err != nil || capacity < 1 || capacity > 100 {
+					row.fail("capacity must be a whole number between 1 and 100")
+					continue
+				}
+			}
+			row.capacity = &capacity
+
+		case "active":
+			active, ok := parseImportBool(value)
+			if !ok {
+				row.fail("active must be yes/no, true/false or 1/0")
+				continue
+			}
+			row.active = &active
+		}
+	}
+
+	return row
+}
+
+// parseImportBool parses the boolean spellings spreadsheets commonly use. Empty means true.
+func parseImportBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "", "1", "true", "yes", "y", "si", "sí", "s", "x":
+		return true, true
+	case "0", "false", "no", "n":
+		return false, true
+	}
+	return false, false
+}
+
+// applyTo copies the row's columns onto the table
+func (r *importRow) applyTo(table *domain.Table) {
+	if r.label != nil {
+		table.Label = *r.label
+	}
+	if r.zoneID != nil {
+		table.ZoneID = *r.zoneID
+	}
+	if r.capacity != nil {
+		table.Capacity = *r.capacity
+	}
+	if r.active != nil {
+		table.IsActive = *r.active
+	}
+}
+
+// fail adds an error to the row's report
+func (r *importRow) fail(format string, args ...interface{}) {
+	r.report.Errors = append(r.report.Errors, fmt.Sprintf(format, args...))
+}
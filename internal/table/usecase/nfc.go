@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"juansecalvinio/tepidolacuenta/internal/table/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetNFCTag returns the NDEF message to write on a table's NFC sticker. It points
+// at the same signed request URL as the table's QR code, so rotating the QR
+// revokes the tag too.
+func (uc *tableUseCase) GetNFCTag(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) (*domain.NFCTag, error) {
+	table, err := uc.GetByID(ctx, id, userID, restaurantIDHint)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewNFCTag(table), nil
+}
+
+// ListBranchNFCTags returns the NFC tag payloads of a branch's active tables, ordered by number
+func (uc *tableUseCase) ListBranchNFCTags(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) ([]*domain.NFCTag, error) {
+	if _, err := uc.verifyBranchAccess(ctx, branchID, userID, restaurantIDHint); err != nil {
+		return nil, err
+	}
+
+	tables, err := uc.repo.FindByBranchID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Number < tables[j].Number })
+
+	tags := make([]*domain.NFCTag, 0, len(tables))
+	for _, table := range tables {
+		if table.IsActive {
+			tags = append(tags, domain.NewNFCTag(table))
+		}
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/table/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportBranchQRCodes prepares a ZIP with one QR image per active table of a
+// branch, named mesa-<number>.<format>, plus a manifest.csv with the number, ID
+// and encoded URL of each table. Access is checked and tables are loaded up
+// front; the images are only rendered when the export is written.
+func (uc *tableUseCase) ExportBranchQRCodes(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, params domain.QRExportParams) (*domain.QRExport, error) {
+	if _, err := uc.verifyBranchAccess(ctx, branchID, userID, restaurantIDHint); err != nil {
+		return nil, err
+	}
+
+	allTables, err := uc.repo.FindByBranchID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]*domain.Table, 0, len(allTables))
+	for _, table := range allTables {
+		if table.IsActive {
+			tables = append(tables, table)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("%w: the branch has no active tables", pkg.ErrInvalidInput)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Number < tables[j].Number })
+
+	format := params.Format
+	if format == "" {
+		format = domain.QRFormatPNG
+	}
+
+	level, opts, err := uc.qrRenderSettings(params.QRImageParams)
+	if err != nil {
+		return nil, err
+	}
+
+	write := func(w io.Writer) error {
+		archive := zip.NewWriter(w)
+		now := time.Now()
+
+		manifest := [][]string{{"number", "id", "url"}}
+		for _, table := range tables {
+			data, _, err := renderQRCode(table.ScanURL(), format, level, opts)
+			if err != nil {
+				return fmt.Errorf("render table %d: %w", table.Number, err)
+			}
+
+			// PNGs are already compressed
+			method := zip.Deflate
+			if format == domain.QRFormatPNG {
+				method = zip.Store
+			}
+			file, err := archive.CreateHeader(&zip.FileHeader{
+				Name:     fmt.Sprintf("mesa-%d.%s", table.Number, format),
+				Method:   method,
+				Modified: now,
+			})
+			if err != nil {
+				return err
+			}
+			if _, err := file.Write(data); err != nil {
+				return err
+			}
+
+			manifest = append(manifest, []string{strconv.Itoa(table.Number), table.ID.Hex(), table.ScanURL()})
+		}
+
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: "manifest.csv", Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		if err := csv.NewWriter(file).WriteAll(manifest); err != nil {
+			return err
+		}
+
+		return archive.Close()
+	}
+
+	return &domain.QRExport{
+		Filename: fmt.Sprintf("qr-export-%s.zip", branchID.Hex()),
+		Write:    write,
+	}, nil
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/pkg/pdf"
+	"juansecalvinio/tepidolacuenta/internal/pkg/qrcode"
+	"juansecalvinio/tepidolacuenta/internal/table/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Defaults and geometry for printable QR sheets, in points
+const (
+	defaultQRSheetColumns = 3
+	defaultQRSheetRows    = 4
+	qrSheetMargin         = 36 // Half an inch, leaves room for the crop marks
+	qrSheetCellPadding    = 6
+	qrSheetQuietZone      = 4
+	cropMarkOffset        = 6
+	cropMarkLength        = 18
+	cropMarkWidth         = 0.25
+)
+
+// RenderBranchQRSheet lays out the QR codes of a branch's tables in a print-ready
+// PDF grid with crop marks. Each cell has the QR, the table number, the restaurant
+// name and the branch address.
+func (uc *tableUseCase) RenderBranchQRSheet(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, params domain.QRSheetParams) (*domain.QRImage, error) {
+	restaurantID, err := uc.verifyBranchAccess(ctx, branchID, userID, restaurantIDHint)
+	if err != nil {
+		return nil, err
+	}
+
+	branch, err := uc.branchRepo.FindByID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, *restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	allTables, err := uc.repo.FindByBranchID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]*domain.Table, 0, len(allTables))
+	for _, table := range allTables {
+		if table.IsActive || params.IncludeInactive {
+			tables = append(tables, table)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("%w: the branch has no tables to print", pkg.ErrInvalidInput)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Number < tables[j].Number })
+
+	paper := pdf.A4
+	if params.Paper == "letter" {
+		paper = pdf.Letter
+	}
+
+	columns, rows := defaultQRSheetColumns, defaultQRSheetRows
+	if params.Columns != 0 {
+		columns = params.Columns
+	}
+	if params.Rows != 0 {
+		rows = params.Rows
+	}
+
+	level := qrcode.LevelM
+	if params.ECC != "" {
+		level, _ = qrcode.ParseLevel(params.ECC)
+	}
+
+	data, err := buildQRSheet(tables, paper, columns, rows, level, restaurant.Name, branch.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.QRImage{
+		Data:        data,
+		ContentType: "application/pdf",
+		Filename:    fmt.Sprintf("qr-sheet-%s.pdf", branch.ID.Hex()),
+	}, nil
+}
+
+// buildQRSheet renders the tables' stickers in a columns x rows grid, starting a
+// new page whenever one fills up
+func buildQRSheet(tables []*domain.Table, paper pdf.Size, columns, rows int, level qrcode.Level, restaurantName, branchAddress string) ([]byte, error) {
+	doc := pdf.New(paper)
+	perPage := columns * rows
+	var page *pdf.Page
+	for i, table := range tables {
+		if i%perPage == 0 {
+			page = doc.AddPage()
+			drawCropMarks(page, paper, columns, rows)
+		}
+
+		code, err := qrcode.Encode(table.ScanURL(), level)
+		if err != nil {
+			return nil, err
+		}
+
+		cell := i % perPage
+		drawQRSheetCell(page, paper, columns, rows, cell%columns, cell/columns, code, table.DisplayName(), restaurantName, branchAddress)
+	}
+
+	return doc.Bytes()
+}
+
+// qrSheetCellSize returns the width and height of a grid cell
+func qrSheetCellSize(paper pdf.Size, columns, rows int) (float64, float64) {
+	return (paper.Width - 2*qrSheetMargin) / float64(columns), (paper.Height - 2*qrSheetMargin) / float64(rows)
+}
+
+// drawCropMarks draws cut marks in the page margin for every grid line,
+// plus small crosses where inner cut lines meet
+func drawCropMarks(page *pdf.Page, paper pdf.Size, columns, rows int) {
+	cellW, cellH := qrSheetCellSize(paper, columns, rows)
+	top, right := paper.Height-qrSheetMargin, paper.Width-qrSheetMargin
+
+	for i := 0; i <= columns; i++ {
+		x := qrSheetMargin + float64(i)*cellW
+		page.Line(x, top+cropMarkOffset, x, top+cropMarkOffset+cropMarkLength, cropMarkWidth)
+		page.Line(x, qrSheetMargin-cropMarkOffset, x, qrSheetMargin-cropMarkOffset-cropMarkLength, cropMarkWidth)
+	}
+	for j := 0; j <= rows; j++ {
+		y := qrSheetMargin + float64(j)*cellH
+		page.Line(qrSheetMargin-cropMarkOffset, y, qrSheetMargin-cropMarkOffset-cropMarkLength, y, cropMarkWidth)
+		page.Line(right+cropMarkOffset, y, right+cropMarkOffset+cropMarkLength, y, cropMarkWidth)
+	}
+
+	const cross = 4
+	for i := 1; i < columns; i++ {
+		for j := 1; j < rows; j++ {
+			x, y := qrSheetMargin+float64(i)*cellW, qrSheetMargin+float64(j)*cellH
+			page.Line(x-cross, y, x+cross, y, cropMarkWidth)
+			page.Line(x, y-cross, x, y+cross, cropMarkWidth)
+		}
+	}
+}
+
+// drawQRSheetCell draws one sticker: the QR on top and the labels centered below it
+func drawQRSheetCell(page *pdf.Page, paper pdf.Size, columns, rows, col, row int, code *qrcode.Code, tableName, restaurantName, branchAddress string) {
+	cellW, cellH := qrSheetCellSize(paper, columns, rows)
+	left := qrSheetMargin + float64(col)*cellW
+	top := paper.Height - qrSheetMargin - float64(row)*cellH
+	centerX := left + cellW/2
+
+	// Text scales with the cell so small grids stay legible and big ones don't look empty
+	labelSize := min(max(min(cellW, cellH)*0.045, 6), 12)
+	numberSize := labelSize * 1.8
+	textHeight := numberSize + 2*labelSize*1.3 + labelSize
+
+	qrSide := min(cellW, cellH-textHeight) - 2*qrSheetCellPadding
+	module := qrSide / float64(code.Size+2*qrSheetQuietZone)
+	qrLeft := centerX - qrSide/2 + qrSheetQuietZone*module
+	qrTop := top - qrSheetCellPadding - qrSheetQuietZone*module
+
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; {
+			if !code.Modules[y][x] {
+				x++
+				continue
+			}
+			run := 1
+			for x+run < code.Size && code.Modules[y][x+run] {
+				run++
+			}
+			page.FillRect(qrLeft+float64(x)*module, qrTop-float64(y+1)*module, float64(run)*module, module)
+			x += run
+		}
+	}
+
+	maxTextWidth := cellW - 2*qrSheetCellPadding
+	baseline := top - qrSheetCellPadding - qrSide - numberSize*0.8
+	drawCenteredText(page, centerX, baseline, pdf.HelveticaBold, numberSize, tableName, maxTextWidth)
+
+	baseline -= labelSize * 1.6
+	drawCenteredText(page, centerX, baseline, pdf.HelveticaBold, labelSize, restaurantName, maxTextWidth)
+
+	baseline -= labelSize * 1.3
+	drawCenteredText(page, centerX, baseline, pdf.Helvetica, labelSize, branchAddress, maxTextWidth)
+}
+
+// drawCenteredText draws a line of text centered on x, cutting it with an
+// ellipsis when it's wider than maxWidth
+func drawCenteredText(page *pdf.Page, x, y float64, font pdf.Font, size float64, text string, maxWidth float64) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+
+	if pdf.TextWidth(font, size, text) > maxWidth {
+		runes := []rune(text)
+		for len(runes) > 0 && pdf.TextWidth(font, size, string(runes)+"...") > maxWidth {
+			runes = runes[:len(runes)-1]
+		}
+		text = strings.TrimSpace(string(runes)) + "..."
+	}
+
+	page.Text(x-pdf.TextWidth(font, size, text)/2, y, font, size, text)
+}
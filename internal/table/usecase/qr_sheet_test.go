@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg/pdf"
+	"juansecalvinio/tepidolacuenta/internal/pkg/qrcode"
+	"juansecalvinio/tepidolacuenta/internal/table/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	startxrefPattern = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`)
+	xrefEntryPattern = regexp.MustCompile(`^(\d{10}) 00000 n \n`)
+	objectPattern    = regexp.MustCompile(`^(\d+) 0 obj\n`)
+)
+
+func TestBuildQRSheetXref(t *testing.T) {
+	branchID := primitive.NewObjectID()
+	tables := make([]*domain.Table, 14)
+	for i := range tables {
+		tables[i] = domain.NewTable(branchID, i+1, fmt.Sprintf("https://tepidolacuenta.com/request?k=token%02d&h=hash", i+1))
+	}
+	tables[3].Label = "Terraza (ventana)"
+	tables[5].ShortURL = "https://tpl.cu/AbC123"
+
+	for _, paper := range []pdf.Size{pdf.A4, pdf.Letter} {
+		data, err := buildQRSheet(tables, paper, 3, 4, qrcode.LevelM, "La Esquina de Ñoño", "Av. Corrientes 1234, CABA")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := startxrefPattern.FindSubmatch(data)
+		if m == nil {
+			t.Fatalf("missing startxref trailer: %q", data[max(len(data)-64, 0):])
+		}
+		xref, _ := strconv.Atoi(string(m[1]))
+		if want := bytes.LastIndex(data, []byte("\nxref\n")) + 1; xref != want {
+			t.Fatalf("startxref = %d, xref table is at %d", xref, want)
+		}
+
+		// 14 tables on a 3x4 grid take two pages; catalog, page tree and two fonts
+		// come first, then a page and its content stream per page
+		const objects = 4 + 2*2
+		header := fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", objects+1)
+		if !bytes.HasPrefix(data[xref:], []byte(header)) {
+			t.Fatalf("xref table starts with %q, want %q", data[xref:xref+len(header)], header)
+		}
+
+		entries := data[xref+len(header):]
+		for n := 1; n <= objects; n++ {
+			entry := xrefEntryPattern.FindSubmatch(entries)
+			if entry == nil {
+				t.Fatalf("malformed xref entry for object %d: %q", n, entries[:min(20, len(entries))])
+			}
+			entries = entries[20:]
+
+			offset, _ := strconv.Atoi(string(entry[1]))
+			obj := objectPattern.FindSubmatch(data[offset:])
+			if obj == nil || string(obj[1]) != strconv.Itoa(n) {
+				t.Errorf("xref offset %d of object %d points at %q", offset, n, data[offset:min(offset+16, len(data))])
+			}
+		}
+		if !bytes.HasPrefix(entries, []byte("trailer\n")) {
+			t.Errorf("expected trailer after the xref entries, got %q", entries[:min(16, len(entries))])
+		}
+	}
+}
@@ -4,14 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"image"
+	"io"
+	"strings"
+	"time"
 
 	branchRepo "juansecalvinio/tepidolacuenta/internal/branch/repository"
+	"juansecalvinio/tepidolacuenta/internal/database"
 	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/pkg/qrcode"
 	restaurantRepo "juansecalvinio/tepidolacuenta/internal/restaurant/repository"
 	subscriptionDomain "juansecalvinio/tepidolacuenta/internal/subscription/domain"
 	subscriptionRepo "juansecalvinio/tepidolacuenta/internal/subscription/repository"
 	"juansecalvinio/tepidolacuenta/internal/table/domain"
 	"juansecalvinio/tepidolacuenta/internal/table/repository"
+	zoneRepo "juansecalvinio/tepidolacuenta/internal/zone/repository"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -24,33 +31,57 @@ type UseCase interface {
 	GetByBranchID(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) ([]*domain.Table, error)
 	Update(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.UpdateTableInput) (*domain.Table, error)
 	Delete(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) error
+	BlockPublicRequests(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.BlockTableInput) (*domain.Table, error)
+	UnblockPublicRequests(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*domain.Table, error)
+	ClearFlag(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*domain.Table, error)
+	RotateQR(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*domain.Table, error)
+	RenderBranchQRSheet(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, params domain.QRSheetParams) (*domain.QRImage, error)
+	GetNFCTag(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) (*domain.NFCTag, error)
+	ListBranchNFCTags(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) ([]*domain.NFCTag, error)
+	ExportBranchQRCodes(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, params domain.QRExportParams) (*domain.QRExport, error)
+	RenderQR(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, format string, params domain.QRImageParams) (*domain.QRImage, error)
+	BulkAction(ctx context.Context, userID primitive.ObjectID, input domain.BulkTableActionInput) (*domain.BulkTableActionResult, error)
+	ImportTables(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, data io.Reader, params domain.TableImportParams) (*domain.TableImportReport, error)
+	SaveFloorPlan(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, input domain.FloorPlanInput) ([]*domain.Table, error)
 }
 
 type tableUseCase struct {
 	repo             repository.Repository
+	shortLinkRepo    repository.ShortLinkRepository
 	branchRepo       branchRepo.Repository
 	restaurantRepo   restaurantRepo.Repository
+	zoneRepo         zoneRepo.Repository
 	subscriptionRepo subscriptionRepo.SubscriptionRepository
 	planRepo         subscriptionRepo.PlanRepository
+	tx               database.Transactor
 	qrService        *pkg.QRService
+	qrLogo           image.Image
 }
 
 // NewTableUseCase creates a new table use case
 func NewTableUseCase(
 	repo repository.Repository,
+	shortLinkRepo repository.ShortLinkRepository,
 	branchRepo branchRepo.Repository,
 	restaurantRepo restaurantRepo.Repository,
+	zoneRepo zoneRepo.Repository,
 	subscriptionRepo subscriptionRepo.SubscriptionRepository,
 	planRepo subscriptionRepo.PlanRepository,
+	tx database.Transactor,
 	qrService *pkg.QRService,
+	qrLogo image.Image,
 ) UseCase {
 	return &tableUseCase{
 		repo:             repo,
+		shortLinkRepo:    shortLinkRepo,
 		branchRepo:       branchRepo,
 		restaurantRepo:   restaurantRepo,
+		zoneRepo:         zoneRepo,
 		subscriptionRepo: subscriptionRepo,
 		planRepo:         planRepo,
+		tx:               tx,
 		qrService:        qrService,
+		qrLogo:           qrLogo,
 	}
 }
 
@@ -114,24 +145,89 @@ func (uc *tableUseCase) Create(ctx context.Context, userID primitive.ObjectID, i
 		return nil, fmt.Errorf("table number %d already exists for this branch", input.Number)
 	}
 
-	// Create table with temporary ID for QR generation
-	tempTable := domain.NewTable(branchID, input.Number, "")
+	label := strings.TrimSpace(input.Label)
+	if err := uc.checkLabelAvailable(ctx, branchID, label, primitive.NilObjectID); err != nil {
+		return nil, err
+	}
+
+	var zoneID *primitive.ObjectID
+	if input.ZoneID != "" {
+		if zoneID, err = uc.resolveZone(ctx, branchID, input.ZoneID); err != nil {
+			return nil, err
+		}
+	}
 
-	// Save to get the actual ID
-	if err := uc.repo.Create(ctx, tempTable); err != nil {
+	table := domain.NewTableWithQR(uc.qrService, branchID, input.Number)
+	table.Label = label
+	table.Capacity = input.Capacity
+	table.Priority = input.Priority
+	table.ZoneID = zoneID
+
+	// The short link and the table are stored together, so a failed insert leaves no orphaned link
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.issueShortLink(ctx, table); err != nil {
+			return err
+		}
+		return uc.repo.Create(ctx, table)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Generate QR code with the actual table ID
-	qrCode := uc.qrService.GenerateTableQRCode(*restaurantID, branchID, tempTable.ID, input.Number)
-	tempTable.QRCode = qrCode
+	return table, nil
+}
 
-	// Update with QR code
-	if err := uc.repo.Update(ctx, tempTable); err != nil {
+// issueShortLink creates a short link for the table's current QR version and points the table at it
+func (uc *tableUseCase) issueShortLink(ctx context.Context, table *domain.Table) error {
+	link := domain.NewTableShortLink(uc.qrService, table)
+	if err := uc.shortLinkRepo.Create(ctx, link, uc.qrService.GenerateSlug); err != nil {
+		return err
+	}
+
+	// The slug may have been redrawn on a collision
+	table.PointAtShortLink(uc.qrService, link)
+	return nil
+}
+
+// checkLabelAvailable verifies no other table of the branch uses the label.
+// Empty labels are never taken; tableID is the table being relabeled, if any.
+func (uc *tableUseCase) checkLabelAvailable(ctx context.Context, branchID primitive.ObjectID, label string, tableID primitive.ObjectID) error {
+	if label == "" {
+		return nil
+	}
+
+	existing, err := uc.repo.FindByBranchAndLabel(ctx, branchID, label)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.ID != tableID {
+		return fmt.Errorf("%w: label %q is already used by table %d", pkg.ErrInvalidInput, label, existing.Number)
+	}
+
+	return nil
+}
+
+// resolveZone parses a zone ID and checks the zone belongs to the branch
+func (uc *tableUseCase) resolveZone(ctx context.Context, branchID primitive.ObjectID, zoneIDStr string) (*primitive.ObjectID, error) {
+	zoneID, err := primitive.ObjectIDFromHex(zoneIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid zone ID", pkg.ErrInvalidInput)
+	}
+
+	zone, err := uc.zoneRepo.FindByID(ctx, zoneID)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			return nil, fmt.Errorf("%w: zone not found", pkg.ErrInvalidInput)
+		}
 		return nil, err
 	}
 
-	return tempTable, nil
+	if zone.BranchID != branchID {
+		return nil, fmt.Errorf("%w: zone belongs to another branch", pkg.ErrInvalidInput)
+	}
+
+	return &zone.ID, nil
 }
 
 // BulkCreate creates multiple tables for a branch
@@ -167,29 +263,28 @@ func (uc *tableUseCase) BulkCreate(ctx context.Context, userID primitive.ObjectI
 		}
 	}
 
-	// Create tables
+	// Build every table and short link up front, then insert them all or nothing
 	createdTables := make([]*domain.Table, 0, input.Count)
+	links := make([]*domain.ShortLink, 0, input.Count)
 	for i := 1; i <= input.Count; i++ {
-		tableNumber := maxTableNumber + i
+		newTable := domain.NewTableWithQR(uc.qrService, branchID, maxTableNumber+i)
+		link := domain.NewTableShortLink(uc.qrService, newTable)
 
-		// Create table
-		newTable := domain.NewTable(branchID, tableNumber, "")
+		createdTables = append(createdTables, newTable)
+		links = append(links, link)
+	}
 
-		// Save to get the actual ID
-		if err := uc.repo.Create(ctx, newTable); err != nil {
-			return nil, fmt.Errorf("failed to create table %d: %w", tableNumber, err)
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.shortLinkRepo.CreateMany(ctx, links); err != nil {
+			return fmt.Errorf("failed to create short links: %w", err)
 		}
-
-		// Generate QR code with the actual table ID
-		qrCode := uc.qrService.GenerateTableQRCode(*restaurantID, branchID, newTable.ID, tableNumber)
-		newTable.QRCode = qrCode
-
-		// Update with QR code
-		if err := uc.repo.Update(ctx, newTable); err != nil {
-			return nil, fmt.Errorf("failed to update QR code for table %d: %w", tableNumber, err)
+		if err := uc.repo.CreateMany(ctx, createdTables); err != nil {
+			return fmt.Errorf("failed to create tables: %w", err)
 		}
-
-		createdTables = append(createdTables, newTable)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return createdTables, nil
@@ -234,9 +329,8 @@ func (uc *tableUseCase) Update(ctx context.Context, id primitive.ObjectID, userI
 		return nil, err
 	}
 
-	// Update fields if provided
-	needsQRUpdate := false
-
+	// Update fields if provided.
+	// The QR code only carries the table token, so renumbering keeps printed codes valid
 	if input.Number != 0 {
 		if !pkg.IsValidTableNumber(input.Number) {
 			return nil, errors.New("table number must be greater than 0")
@@ -253,17 +347,48 @@ func (uc *tableUseCase) Update(ctx context.Context, id primitive.ObjectID, userI
 		}
 
 		table.Number = input.Number
-		needsQRUpdate = true
 	}
 
+	if input.Label != nil {
+		label := strings.TrimSpace(*input.Label)
+		if err := uc.checkLabelAvailable(ctx, table.BranchID, label, table.ID); err != nil {
+			return nil, err
+		}
+		table.Label = label
+	}
+
+	if input.Capacity != nil {
+		table.Capacity = *input.Capacity
+	}
+
+	reactivated := false
 	if input.IsActive != nil {
+		reactivated = *input.IsActive && !table.IsActive
 		table.IsActive = *input.IsActive
 	}
 
-	// Regenerate QR code if number changed
-	if needsQRUpdate {
-		qrCode := uc.qrService.GenerateTableQRCode(*restaurantID, table.BranchID, table.ID, table.Number)
-		table.QRCode = qrCode
+	if input.Priority != nil {
+		table.Priority = *input.Priority
+	}
+
+	if input.ZoneID != nil {
+		table.ZoneID = nil
+		if *input.ZoneID != "" {
+			if table.ZoneID, err = uc.resolveZone(ctx, table.BranchID, *input.ZoneID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Reactivating a table must keep the branch within the plan, which may have been downgraded
+	if reactivated {
+		err = uc.activateTables(ctx, *restaurantID, table.BranchID, []primitive.ObjectID{table.ID}, func(ctx context.Context) error {
+			return uc.repo.Update(ctx, table)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return table, nil
 	}
 
 	// Save changes
@@ -293,18 +418,13 @@ func (uc *tableUseCase) Delete(ctx context.Context, id primitive.ObjectID, userI
 
 // checkTablePlanLimit verifies the restaurant's plan allows adding `count` more tables across all its branches
 func (uc *tableUseCase) checkTablePlanLimit(ctx context.Context, restaurantID, branchID primitive.ObjectID, count int) error {
-	subscription, err := uc.subscriptionRepo.FindByRestaurantID(ctx, restaurantID)
-	if err != nil {
-		return pkg.ErrPlanLimitReached
-	}
-
-	plan, err := uc.planRepo.FindByID(ctx, subscription.PlanID)
+	maxTables, err := uc.planMaxTables(ctx, restaurantID)
 	if err != nil {
 		return err
 	}
 
 	// Unlimited tables
-	if plan.MaxTables == subscriptionDomain.Unlimited {
+	if maxTables == subscriptionDomain.Unlimited {
 		return nil
 	}
 
@@ -314,9 +434,186 @@ func (uc *tableUseCase) checkTablePlanLimit(ctx context.Context, restaurantID, b
 		return err
 	}
 
-	if len(tables)+count > plan.MaxTables {
+	if len(tables)+count > maxTables {
 		return pkg.ErrPlanLimitReached
 	}
 
 	return nil
 }
+
+// planMaxTables returns how many tables per branch the restaurant's plan allows.
+// Restaurants without a subscription get pkg.ErrPlanLimitReached.
+func (uc *tableUseCase) planMaxTables(ctx context.Context, restaurantID primitive.ObjectID) (int, error) {
+	subscription, err := uc.subscriptionRepo.FindByRestaurantID(ctx, restaurantID)
+	if err != nil {
+		return 0, pkg.ErrPlanLimitReached
+	}
+
+	plan, err := uc.planRepo.FindByID(ctx, subscription.PlanID)
+	if err != nil {
+		return 0, err
+	}
+
+	return plan.MaxTables, nil
+}
+
+// findOwnedTable loads a table and verifies the caller owns its branch
+func (uc *tableUseCase) findOwnedTable(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*domain.Table, error) {
+	table, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.verifyBranchAccess(ctx, table.BranchID, userID, nil); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// BlockPublicRequests rejects new public requests for the table for the given time,
+// without deactivating it
+func (uc *tableUseCase) BlockPublicRequests(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.BlockTableInput) (*domain.Table, error) {
+	table, err := uc.findOwnedTable(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	until := time.Now().Add(time.Duration(input.Minutes) * time.Minute)
+	if err := uc.repo.SetPublicBlockedUntil(ctx, table.ID, &until); err != nil {
+		return nil, err
+	}
+
+	table.PublicBlockedUntil = &until
+	return table, nil
+}
+
+// UnblockPublicRequests lifts a public request block before it expires
+func (uc *tableUseCase) UnblockPublicRequests(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*domain.Table, error) {
+	table, err := uc.findOwnedTable(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.SetPublicBlockedUntil(ctx, table.ID, nil); err != nil {
+		return nil, err
+	}
+
+	table.PublicBlockedUntil = nil
+	return table, nil
+}
+
+// ClearFlag marks an abuse flag as reviewed
+func (uc *tableUseCase) ClearFlag(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*domain.Table, error) {
+	table, err := uc.findOwnedTable(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.ClearFlag(ctx, table.ID); err != nil {
+		return nil, err
+	}
+
+	table.FlaggedAt = nil
+	table.FlagReason = ""
+	return table, nil
+}
+
+// RotateQR re-issues the table's QR code and short link under a new version, so
+// stickers printed before the rotation stop working. The table token stays the same.
+func (uc *tableUseCase) RotateQR(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*domain.Table, error) {
+	table, err := uc.findOwnedTable(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	table.QRVersion++
+	table.QRCode = uc.qrService.GenerateTableQRCode(table.Token, table.QRVersion)
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.issueShortLink(ctx, table); err != nil {
+			return err
+		}
+		return uc.repo.Update(ctx, table)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// Defaults for rendered QR images
+const (
+	defaultQRImageSize = 512
+	defaultQRQuietZone = 4
+)
+
+// RenderQR renders the table's QR URL as a PNG or SVG image.
+// Asking for the logo forces level H so the covered modules can be recovered.
+func (uc *tableUseCase) RenderQR(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID, format string, params domain.QRImageParams) (*domain.QRImage, error) {
+	table, err := uc.GetByID(ctx, id, userID, restaurantIDHint)
+	if err != nil {
+		return nil, err
+	}
+
+	level, opts, err := uc.qrRenderSettings(params)
+	if err != nil {
+		return nil, err
+	}
+
+	qrImage := &domain.QRImage{Filename: fmt.Sprintf("table-%d.%s", table.Number, format)}
+	qrImage.Data, qrImage.ContentType, err = renderQRCode(table.ScanURL(), format, level, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrImage, nil
+}
+
+// qrRenderSettings returns the error correction level and render options for the
+// given image params, filling in the defaults
+func (uc *tableUseCase) qrRenderSettings(params domain.QRImageParams) (qrcode.Level, qrcode.Options, error) {
+	level := qrcode.LevelM
+	if params.ECC != "" {
+		level, _ = qrcode.ParseLevel(params.ECC)
+	}
+
+	opts := qrcode.Options{
+		Size:      defaultQRImageSize,
+		QuietZone: defaultQRQuietZone,
+	}
+	if params.Size != 0 {
+		opts.Size = params.Size
+	}
+	if params.QuietZone != nil {
+		opts.QuietZone = *params.QuietZone
+	}
+	if params.Logo {
+		if uc.qrLogo == nil {
+			return level, opts, fmt.Errorf("%w: no QR logo is configured", pkg.ErrInvalidInput)
+		}
+		opts.Logo = uc.qrLogo
+		level = qrcode.LevelH
+	}
+
+	return level, opts, nil
+}
+
+// renderQRCode encodes url and renders it in the given format. Returns the image and its content type.
+func renderQRCode(url, format string, level qrcode.Level, opts qrcode.Options) ([]byte, string, error) {
+	code, err := qrcode.Encode(url, level)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch format {
+	case domain.QRFormatPNG:
+		data, err := code.PNG(opts)
+		return data, "image/png", err
+	case domain.QRFormatSVG:
+		data, err := code.SVG(opts)
+		return data, "image/svg+xml", err
+	default:
+		return nil, "", fmt.Errorf("%w: unsupported QR format %q", pkg.ErrInvalidInput, format)
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Zone is a section of a branch (salon, terrace, bar) with its own staff.
+// Tables are assigned to at most one zone.
+type Zone struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	BranchID  primitive.ObjectID `json:"branchId" bson:"branch_id"`
+	Name      string             `json:"name" bson:"name"`
+	CreatedAt time.Time          `json:"createdAt" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// CreateZoneInput represents the data needed to create a zone
+type CreateZoneInput struct {
+	BranchID string `json:"branchId" binding:"required"`
+	Name     string `json:"name" binding:"required,max=50"`
+}
+
+// UpdateZoneInput represents the data needed to update a zone
+type UpdateZoneInput struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// NewZone creates a new zone with the current timestamp
+func NewZone(branchID primitive.ObjectID, name string) *Zone {
+	now := time.Now()
+	return &Zone{
+		BranchID:  branchID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,334 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"juansecalvinio/tepidolacuenta/internal/middleware"
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/zone/domain"
+	"juansecalvinio/tepidolacuenta/internal/zone/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Handler struct {
+	useCase usecase.UseCase
+}
+
+// NewZoneHandler creates a new zone handler
+func NewZoneHandler(useCase usecase.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+// Create handles zone creation
+// @Summary Create a zone in a branch
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body domain.CreateZoneInput true "Zone data"
+// @Success 201 {object} pkg.Response{data=domain.Zone}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 409 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/zones [post]
+func (h *Handler) Create(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	var input domain.CreateZoneInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	zone, err := h.useCase.Create(c.Request.Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrZoneNameTaken) {
+			pkg.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to create zone", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusCreated, "Zone created successfully", zone)
+}
+
+// GetByID handles retrieving a zone by ID
+// @Summary Get zone by ID
+// @Tags zones
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Zone ID"
+// @Success 200 {object} pkg.Response{data=domain.Zone}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/zones/{id} [get]
+func (h *Handler) GetByID(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	zoneIDStr := c.Param("id")
+	zoneID, err := primitive.ObjectIDFromHex(zoneIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid zone ID", err)
+		return
+	}
+
+	zone, err := h.useCase.GetByID(c.Request.Context(), zoneID, userID, extractRestaurantIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Zone not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this zone", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this zone", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to get zone", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Zone retrieved successfully", zone)
+}
+
+// ListByBranch handles retrieving all zones of a branch
+// @Summary List zones by branch
+// @Tags zones
+// @Produce json
+// @Security BearerAuth
+// @Param branchId path string true "Branch ID"
+// @Success 200 {object} pkg.Response{data=[]domain.Zone}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/zones/branch/{branchId} [get]
+func (h *Handler) ListByBranch(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	branchIDStr := c.Param("branchId")
+	branchID, err := primitive.ObjectIDFromHex(branchIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid branch ID", err)
+		return
+	}
+
+	zones, err := h.useCase.GetByBranchID(c.Request.Context(), branchID, userID, extractRestaurantIDHint(c))
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Branch not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrForbidden) {
+			pkg.ForbiddenResponse(c, "You don't have access to this branch", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to get zones", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Zones retrieved successfully", zones)
+}
+
+// extractRestaurantIDHint parses the employee's restaurantID from context (nil for owners).
+func extractRestaurantIDHint(c *gin.Context) *primitive.ObjectID {
+	ridStr, ok := middleware.GetUserRestaurantID(c)
+	if !ok {
+		return nil
+	}
+	rid, err := primitive.ObjectIDFromHex(ridStr)
+	if err != nil {
+		return nil
+	}
+	return &rid
+}
+
+// Update handles renaming a zone
+// @Summary Update zone
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Zone ID"
+// @Param input body domain.UpdateZoneInput true "Update data"
+// @Success 200 {object} pkg.Response{data=domain.Zone}
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 409 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/zones/{id} [put]
+func (h *Handler) Update(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	zoneIDStr := c.Param("id")
+	zoneID, err := primitive.ObjectIDFromHex(zoneIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid zone ID", err)
+		return
+	}
+
+	var input domain.UpdateZoneInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		pkg.BadRequestResponse(c, "Invalid input", err)
+		return
+	}
+
+	zone, err := h.useCase.Update(c.Request.Context(), zoneID, userID, input)
+	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidInput) {
+			pkg.BadRequestResponse(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Zone not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this zone", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrZoneNameTaken) {
+			pkg.ErrorResponse(c, http.StatusConflict, err.Error(), err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to update zone", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Zone updated successfully", zone)
+}
+
+// Delete handles zone deletion. The zone's tables are left without a zone.
+// @Summary Delete zone
+// @Tags zones
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Zone ID"
+// @Success 200 {object} pkg.Response
+// @Failure 400 {object} pkg.Response
+// @Failure 401 {object} pkg.Response
+// @Failure 404 {object} pkg.Response
+// @Failure 500 {object} pkg.Response
+// @Router /api/v1/zones/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		pkg.UnauthorizedResponse(c, "User not authenticated", pkg.ErrUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	zoneIDStr := c.Param("id")
+	zoneID, err := primitive.ObjectIDFromHex(zoneIDStr)
+	if err != nil {
+		pkg.BadRequestResponse(c, "Invalid zone ID", err)
+		return
+	}
+
+	if err := h.useCase.Delete(c.Request.Context(), zoneID, userID); err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			pkg.NotFoundResponse(c, "Zone not found", err)
+			return
+		}
+		if errors.Is(err, pkg.ErrUnauthorized) {
+			pkg.UnauthorizedResponse(c, "You don't have access to this zone", err)
+			return
+		}
+		pkg.InternalServerErrorResponse(c, "Failed to delete zone", err)
+		return
+	}
+
+	pkg.SuccessResponse(c, http.StatusOK, "Zone deleted successfully", nil)
+}
+
+// RegisterRoutes registers all zone routes.
+// Read routes are accessible by owners and employees; write routes are owner-only.
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	zones := router.Group("/zones")
+	{
+		zones.GET("/:id", h.GetByID)
+		zones.GET("/branch/:branchId", h.ListByBranch)
+	}
+
+	ownerZones := zones.Group("")
+	ownerZones.Use(middleware.OwnerOnly())
+	{
+		ownerZones.POST("", h.Create)
+		ownerZones.PUT("/:id", h.Update)
+		ownerZones.DELETE("/:id", h.Delete)
+	}
+}
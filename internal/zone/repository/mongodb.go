@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	"juansecalvinio/tepidolacuenta/internal/zone/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB zone repository
+func NewMongoRepository(db *mongo.Database) Repository {
+	return &mongoRepository{
+		collection: db.Collection("zones"),
+	}
+}
+
+func (r *mongoRepository) Create(ctx context.Context, zone *domain.Zone) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.InsertOne(ctx, zone)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return pkg.ErrZoneNameTaken
+		}
+		return err
+	}
+
+	zone.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *mongoRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Zone, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var zone domain.Zone
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&zone)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, pkg.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &zone, nil
+}
+
+func (r *mongoRepository) FindByBranchID(ctx context.Context, branchID primitive.ObjectID) ([]*domain.Zone, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"branch_id": branchID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	zones := make([]*domain.Zone, 0)
+	if err := cursor.All(ctx, &zones); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+func (r *mongoRepository) Update(ctx context.Context, zone *domain.Zone) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	zone.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":       zone.Name,
+			"updated_at": zone.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": zone.ID}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return pkg.ErrZoneNameTaken
+		}
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return pkg.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *mongoRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return pkg.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"juansecalvinio/tepidolacuenta/internal/zone/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Repository defines the interface for zone persistence operations
+type Repository interface {
+	// Create returns pkg.ErrZoneNameTaken if the branch already has a zone with that name
+	Create(ctx context.Context, zone *domain.Zone) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Zone, error)
+	FindByBranchID(ctx context.Context, branchID primitive.ObjectID) ([]*domain.Zone, error)
+	// Update returns pkg.ErrZoneNameTaken if the branch already has a zone with that name
+	Update(ctx context.Context, zone *domain.Zone) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	branchRepo "juansecalvinio/tepidolacuenta/internal/branch/repository"
+	"juansecalvinio/tepidolacuenta/internal/pkg"
+	restaurantRepo "juansecalvinio/tepidolacuenta/internal/restaurant/repository"
+	tableRepo "juansecalvinio/tepidolacuenta/internal/table/repository"
+	"juansecalvinio/tepidolacuenta/internal/zone/domain"
+	"juansecalvinio/tepidolacuenta/internal/zone/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UseCase defines the interface for zone use cases
+type UseCase interface {
+	Create(ctx context.Context, userID primitive.ObjectID, input domain.CreateZoneInput) (*domain.Zone, error)
+	GetByID(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) (*domain.Zone, error)
+	GetByBranchID(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) ([]*domain.Zone, error)
+	Update(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.UpdateZoneInput) (*domain.Zone, error)
+	Delete(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) error
+}
+
+type zoneUseCase struct {
+	repo           repository.Repository
+	branchRepo     branchRepo.Repository
+	restaurantRepo restaurantRepo.Repository
+	tableRepo      tableRepo.Repository
+}
+
+// NewZoneUseCase creates a new zone use case
+func NewZoneUseCase(
+	repo repository.Repository,
+	branchRepo branchRepo.Repository,
+	restaurantRepo restaurantRepo.Repository,
+	tableRepo tableRepo.Repository,
+) UseCase {
+	return &zoneUseCase{
+		repo:           repo,
+		branchRepo:     branchRepo,
+		restaurantRepo: restaurantRepo,
+		tableRepo:      tableRepo,
+	}
+}
+
+// verifyBranchAccess verifies that a branch exists and the caller can access it.
+// Pass nil for restaurantIDHint when called from owner-only operations.
+func (uc *zoneUseCase) verifyBranchAccess(ctx context.Context, branchID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) error {
+	branch, err := uc.branchRepo.FindByID(ctx, branchID)
+	if err != nil {
+		return err
+	}
+
+	restaurant, err := uc.restaurantRepo.FindByID(ctx, branch.RestaurantID)
+	if err != nil {
+		return err
+	}
+
+	if restaurantIDHint != nil {
+		if restaurant.ID != *restaurantIDHint {
+			return pkg.ErrForbidden
+		}
+	} else {
+		if restaurant.UserID != userID {
+			return pkg.ErrUnauthorized
+		}
+	}
+
+	return nil
+}
+
+// Create creates a new zone in a branch
+func (uc *zoneUseCase) Create(ctx context.Context, userID primitive.ObjectID, input domain.CreateZoneInput) (*domain.Zone, error) {
+	branchID, err := primitive.ObjectIDFromHex(input.BranchID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid branch ID", pkg.ErrInvalidInput)
+	}
+
+	if err := uc.verifyBranchAccess(ctx, branchID, userID, nil); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: zone name is required", pkg.ErrInvalidInput)
+	}
+
+	zone := domain.NewZone(branchID, name)
+	if err := uc.repo.Create(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+// GetByID retrieves a zone by ID
+func (uc *zoneUseCase) GetByID(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) (*domain.Zone, error) {
+	zone, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.verifyBranchAccess(ctx, zone.BranchID, userID, restaurantIDHint); err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+// GetByBranchID retrieves all zones of a branch, ordered by name
+func (uc *zoneUseCase) GetByBranchID(ctx context.Context, branchID primitive.ObjectID, userID primitive.ObjectID, restaurantIDHint *primitive.ObjectID) ([]*domain.Zone, error) {
+	if err := uc.verifyBranchAccess(ctx, branchID, userID, restaurantIDHint); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.FindByBranchID(ctx, branchID)
+}
+
+// Update renames a zone
+func (uc *zoneUseCase) Update(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, input domain.UpdateZoneInput) (*domain.Zone, error) {
+	zone, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.verifyBranchAccess(ctx, zone.BranchID, userID, nil); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: zone name is required", pkg.ErrInvalidInput)
+	}
+	zone.Name = name
+
+	if err := uc.repo.Update(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+// Delete deletes a zone. Its tables are left without a zone.
+func (uc *zoneUseCase) Delete(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) error {
+	zone, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.verifyBranchAccess(ctx, zone.BranchID, userID, nil); err != nil {
+		return err
+	}
+
+	if err := uc.tableRepo.ClearZone(ctx, zone.ID); err != nil {
+		return err
+	}
+
+	return uc.repo.Delete(ctx, id)
+}